@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	reader "github.com/alanpjohn/go-cdcl/pkg/io"
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+	proof "github.com/alanpjohn/go-cdcl/pkg/proof"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+Checks a DRAT or LRAT proof of unsatisfiability of a DIMCAS file
+
+The check-proof command
+*/
+func checkProof(cCtx *cli.Context) error {
+	logger.Verbosity = cCtx.Bool("verbose")
+
+	if cCtx.NArg() != 2 {
+		return handler.Throw("Expected a DIMCAS file and a proof file", nil)
+	}
+
+	sat, err := reader.ReadFile(cCtx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	proofFile, err := os.Open(cCtx.Args().Get(1))
+	if err != nil {
+		return handler.Throw("The proof file could not be opened", err)
+	}
+	defer proofFile.Close()
+
+	checker := proof.ConstructChecker(sat)
+	var result proof.Result
+
+	switch cCtx.String("format") {
+	case "drat":
+		steps, err := proof.ParseDRAT(proofFile)
+		if err != nil {
+			return err
+		}
+		result = checker.CheckDRAT(steps)
+	case "lrat":
+		if cCtx.String("lrat") != "" {
+			return handler.Throw("LRAT output is only available for DRAT proofs", nil)
+		}
+		steps, err := proof.ParseLRAT(proofFile)
+		if err != nil {
+			return err
+		}
+		result = checker.CheckLRAT(steps)
+	default:
+		return handler.Throw("Unknown proof format: "+cCtx.String("format"), nil)
+	}
+
+	if !result.Verified {
+		fmt.Println("c " + result.Reason)
+		fmt.Println("s NOT VERIFIED")
+		return cli.Exit("", 1)
+	}
+
+	fmt.Printf("c core clauses %v of %v\n", len(result.Core), len(sat.Clauses))
+	fmt.Printf("c core lemmas %v\n", result.Lemmas)
+	fmt.Println("s VERIFIED")
+
+	if path := cCtx.String("core"); path != "" {
+		if err = writeFile(path, func(w *bufio.Writer) error {
+			return reader.Write(w, types.SATFile{AtomCount: sat.AtomCount, Clauses: result.Core})
+		}); err != nil {
+			return err
+		}
+	}
+
+	if path := cCtx.String("lrat"); path != "" {
+		if err = writeFile(path, func(w *bufio.Writer) error {
+			return checker.WriteLRAT(w)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Creates the file at path and writes to it using write
+func writeFile(path string, write func(*bufio.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return handler.Throw("File could not be created: "+path, err)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	if err = write(w); err != nil {
+		return handler.Throw("File could not be written: "+path, err)
+	}
+	if err = w.Flush(); err != nil {
+		return handler.Throw("File could not be written: "+path, err)
+	}
+	return nil
+}
+
+// Command checking proofs of unsatisfiability
+var checkProofCommand = &cli.Command{
+	Name:      "check-proof",
+	Usage:     "Check a DRAT or LRAT proof of unsatisfiability of a DIMCAS file",
+	ArgsUsage: "<dimcas file> <proof file>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "format",
+			Value:    "drat",
+			Usage:    "format of the proof: drat (text or binary) or lrat",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "core",
+			Value:    "",
+			Usage:    "file to write the clauses the proof depends on to, in DIMCAS format",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "lrat",
+			Value:    "",
+			Usage:    "file to write the checked DRAT proof to as an LRAT proof",
+			Required: false,
+		},
+	},
+	Action: checkProof,
+}
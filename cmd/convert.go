@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bufio"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	encode "github.com/alanpjohn/go-cdcl/pkg/encode"
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	reader "github.com/alanpjohn/go-cdcl/pkg/io"
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+Converts a formula between the formats supported by the tool, the formats are guessed from the
+extensions of the files unless they are given
+
+The convert command
+*/
+func convert(cCtx *cli.Context) error {
+	logger.Verbosity = cCtx.Bool("verbose")
+
+	if cCtx.NArg() < 1 || cCtx.NArg() > 2 {
+		return handler.Throw("Expected an input file and an optional output file", nil)
+	}
+	input, output := cCtx.Args().Get(0), cCtx.Args().Get(1)
+
+	from, err := formatOf(cCtx.String("from"), input)
+	if err != nil {
+		return err
+	}
+	to, err := formatOf(cCtx.String("to"), output)
+	if err != nil {
+		return err
+	}
+
+	mode := reader.LENIENT_PARSING
+	if cCtx.Bool("strict") {
+		mode = reader.STRICT_PARSING
+	}
+
+	var sat types.SATFile
+	if input == "-" {
+		sat, err = reader.ReadFormula(os.Stdin, from, mode)
+	} else {
+		sat, err = reader.ReadFormulaFile(input, from, mode)
+	}
+	if err != nil {
+		return err
+	}
+
+	if name := cCtx.String("encode"); name != "" {
+		encoding, err := encode.ParseEncoding(name)
+		if err != nil {
+			return err
+		}
+		if sat, err = encode.Encode(sat, encoding); err != nil {
+			return err
+		}
+	}
+
+	options := reader.WriteOptions{
+		Comments:  cCtx.StringSlice("comment"),
+		Normalize: cCtx.Bool("normalize"),
+	}
+
+	if output == "" || output == "-" {
+		return reader.WriteFormula(os.Stdout, to, sat, options)
+	}
+	return writeFile(output, func(w *bufio.Writer) error {
+		return reader.WriteFormula(w, to, sat, options)
+	})
+}
+
+/*
+Parses the name of a format if it is given, otherwise guesses the format from the filename.
+WCNF formulas and OPB problems are rejected, as soft clauses, pseudo-Boolean constraints and objectives
+have no counterpart in the formats of a SATFile.
+*/
+func formatOf(name string, filename string) (reader.FormulaFormat, error) {
+	if name == "wcnf" || name == "" && reader.IsWCNF(filename) {
+		return reader.DIMCAS_FORMAT, handler.Throw("WCNF formulas cannot be converted, solve them with --maxsat", nil)
+	}
+	if name == "opb" || name == "" && reader.IsOPB(filename) {
+		return reader.DIMCAS_FORMAT, handler.Throw("OPB problems cannot be converted, solve them with --pb", nil)
+	}
+	if name != "" {
+		return reader.ParseFormulaFormat(name)
+	}
+	return reader.FormulaFormatOf(filename), nil
+}
+
+// Command converting formulas
+var convertCommand = &cli.Command{
+	Name:      "convert",
+	Usage:     "Convert a formula between DIMCAS and JSON, WCNF and OPB files are not supported",
+	ArgsUsage: "<input file or -> [output file]",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "from",
+			Value:    "",
+			Usage:    "format of the input: dimacs or json, guessed from the extension if not given",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "to",
+			Value:    "",
+			Usage:    "format of the output: dimacs or json, guessed from the extension if not given",
+			Required: false,
+		},
+		&cli.BoolFlag{
+			Name:     "normalize",
+			Value:    false,
+			Usage:    "sort the literals of every clause and remove repeated literals, repeated clauses and tautologies",
+			Required: false,
+		},
+		&cli.StringFlag{
+			Name:     "encode",
+			Value:    "",
+			Usage:    "encode cardinality constraints into clauses: pairwise, sequential, totalizer, network, bdd or adder",
+			Required: false,
+		},
+		&cli.StringSliceFlag{
+			Name:     "comment",
+			Usage:    "comment written before the DIMCAS header, can be repeated",
+			Required: false,
+		},
+		&cli.BoolFlag{
+			Name:     "strict",
+			Value:    false,
+			Usage:    "reject input whose clauses do not match its header",
+			Required: false,
+		},
+	},
+	Action: convert,
+}
@@ -2,8 +2,12 @@
 package main
 
 import (
+	"bufio"
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/urfave/cli/v2" // CLI framework for a better user experience
 
@@ -33,6 +37,11 @@ Tha Main command
 func solve(cCtx *cli.Context) error {
 	logger.Verbosity = cCtx.Bool("verbose")
 
+	format, err := reader.ParseOutputFormat(cCtx.String("output"))
+	if err != nil {
+		return err
+	}
+
 	filename := cCtx.String("file")
 
 	if filename == "" && !isInputFromPipe() {
@@ -40,16 +49,27 @@ func solve(cCtx *cli.Context) error {
 	}
 
 	var (
-		sol      types.Solver  // The Solver class with the methods implemented for CDCL
-		sat      types.SATFile // Contains all the information extracted as DIMCAS Format
-		err      error
+		sol      types.Solver   // The Solver class with the methods implemented for CDCL
+		sat      types.SATFile  // Contains all the information extracted as DIMCAS Format
 		solution types.Solution // SATISFIABLE or UNSATISFIABLE or UNKNOWN
 	)
 
+	mode := reader.LENIENT_PARSING
+	if cCtx.Bool("strict") {
+		mode = reader.STRICT_PARSING
+	}
+
+	if cCtx.Bool("maxsat") || reader.IsWCNF(filename) {
+		return solveMaxSAT(cCtx, format, mode)
+	}
+	if cCtx.Bool("pb") || reader.IsOPB(filename) {
+		return solvePB(cCtx, format, mode)
+	}
+
 	if isInputFromPipe() {
-		// The input is coming for stdin, our Process function returns an instance of SATFile
+		// The input is coming for stdin, our Parse function returns an instance of SATFile
 		logger.Info("Recieved Input for stdin pipe")
-		if sat, err = reader.Process(os.Stdin); err != nil {
+		if sat, err = reader.Parse(os.Stdin, mode); err != nil {
 			return err
 		}
 	}
@@ -57,20 +77,204 @@ func solve(cCtx *cli.Context) error {
 	if filename != "" {
 		logger.Info("Input from flag")
 		// The input has to read from the file, our Readfile function reads the file
-		// and then calls Process (internally) to return an instance of SATFile
-		if sat, err = reader.ReadFile(filename); err != nil {
+		// and then calls Parse (internally) to return an instance of SATFile
+		if sat, err = reader.ReadFileMode(filename, mode); err != nil {
 			return err
 		}
 	}
 
+	options, err := solverOptions(cCtx, format)
+	if err != nil {
+		return err
+	}
+	options.Core = cCtx.String("core") != ""
+
+	if path := cCtx.String("proof"); path != "" {
+		proofFile, err := os.Create(path)
+		if err != nil {
+			return handler.Throw("Proof file could not be created", err)
+		}
+		defer proofFile.Close()
+		options.Proof = proofFile
+	}
+
 	// Initalize the Solver with the SATFile
-	if sol, err = solver.InitializeBaseSolver(sat, cCtx.Bool("experimental")); err != nil {
+	base, err := solver.InitializeBaseSolver(sat, options)
+	if err != nil {
 		return err
 	}
+	sol = &base
 	logger.Info("Solver initialized")
-	solution, err = sol.Solve() // Get Solution
-	fmt.Print(solution.String())
-	return err
+
+	ctx, stop := searchContext(cCtx)
+	defer stop()
+
+	solution, err = base.SolveContext(ctx) // Get Solution
+	if base.Proof != nil && err == nil {
+		err = base.Proof.Flush()
+	}
+	if solution == types.SATISFIABLE && err == nil {
+		// The model is checked against the clauses as they were read before it is printed
+		var violated []int
+		if violated, err = solver.VerifyModel(sat, sol.Model()); err == nil && len(violated) > 0 {
+			err = handler.Throw("Model violates "+violation(sat, violated[0]), nil)
+		}
+	}
+
+	var core []int
+	if solution == types.UNSATISFIABLE && options.Core && err == nil {
+		core = base.UnsatCore()
+		if cCtx.Bool("mus") {
+			core, err = base.ShrinkCoreContext(ctx)
+		}
+	}
+
+	model := sol.Model()
+	if len(model) > int(sat.AtomCount) {
+		// Selector atoms used for cores are not part of the input
+		model = model[:sat.AtomCount]
+	}
+
+	stats := base.Statistics()
+	result := reader.Result{
+		Solution: solution,
+		Model:    model,
+		Comments: []string{
+			fmt.Sprintf("atoms %v clauses %v", sat.AtomCount, len(sat.Clauses)),
+		},
+		Stats: &stats,
+	}
+	if reason := base.Stopped(); reason != solver.NOT_STOPPED {
+		if core != nil {
+			result.Comments = append(result.Comments, "core is not minimal, search stopped: "+reason.String())
+		} else {
+			result.Comments = append(result.Comments, "search stopped: "+reason.String())
+		}
+	}
+	if core != nil {
+		result.Comments = append(result.Comments, fmt.Sprintf("core clauses %v of %v", len(core), len(sat.Clauses)))
+	}
+	if version != "" {
+		result.Comments = append([]string{"gocdcl " + version}, result.Comments...)
+	}
+	if err != nil {
+		// The run is reported as UNKNOWN instead of exiting with an error
+		result.Solution = types.UNKNOWN
+		result.Comments = append(result.Comments, "ERROR: "+err.Error())
+	}
+
+	if err = reader.WriteResult(os.Stdout, format, result); err != nil {
+		return err
+	}
+
+	if core != nil {
+		clauses := make([]types.Disjunction, len(core))
+		for i, c := range core {
+			clauses[i] = sat.Clauses[c]
+		}
+		if err = writeFile(cCtx.String("core"), func(w *bufio.Writer) error {
+			return reader.Write(w, types.SATFile{AtomCount: sat.AtomCount, Clauses: clauses})
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Exit codes follow the SAT competition: 10 for SATISFIABLE, 20 for UNSATISFIABLE and 0 otherwise
+	if code := reader.ExitCode(result.Solution); code != 0 {
+		return cli.Exit("", code)
+	}
+	return nil
+}
+
+// Builds the solver Options from the flags of the CLI
+func solverOptions(cCtx *cli.Context, format reader.OutputFormat) (solver.Options, error) {
+	options := solver.Options{
+		Experimental: cCtx.Bool("experimental"),
+		Decay:        cCtx.Float64("decay"),
+		Seed:         cCtx.Int64("seed"),
+		PhaseSaving:  cCtx.Bool("phase-saving"),
+		TargetPhase:  cCtx.Bool("target-phase"),
+		Rephase:      cCtx.Uint("rephase"),
+		RestartBase:  cCtx.Uint("restart-base"),
+		RestartGrow:  cCtx.Float64("restart-grow"),
+		Reduce:       cCtx.Uint("reduce"),
+		Minimize:     cCtx.Bool("minimize"),
+		Binary:       cCtx.Bool("binary-minimize"),
+		BinaryProof:  cCtx.Bool("binary-proof"),
+		Conflicts:    cCtx.Uint("conflict-limit"),
+		Decisions:    cCtx.Uint("decision-limit"),
+		Interval:     cCtx.Duration("progress"),
+	}
+
+	if options.Interval > 0 {
+		// Progress lines are `c` lines, which only belong in the competition output
+		options.Progress = os.Stdout
+		if format != reader.COMPETITION_OUTPUT {
+			options.Progress = os.Stderr
+		}
+	}
+
+	switch cCtx.String("formula") {
+	case "watched":
+		options.Formula = solver.WATCHED_FORMULA
+	case "base":
+		options.Formula = solver.BASE_FORMULA
+	default:
+		return options, handler.Throw("Unknown formula: "+cCtx.String("formula"), nil)
+	}
+
+	switch cCtx.String("brancher") {
+	case "vsids":
+		options.Brancher = solver.VSIDS_BRANCHER
+	case "vmtf":
+		options.Brancher = solver.VMTF_BRANCHER
+	case "random":
+		options.Brancher = solver.RANDOM_BRANCHER
+	default:
+		return options, handler.Throw("Unknown brancher: "+cCtx.String("brancher"), nil)
+	}
+
+	switch cCtx.String("polarity") {
+	case "false":
+		options.Polarity = solver.FALSE_POLARITY
+	case "true":
+		options.Polarity = solver.TRUE_POLARITY
+	case "random":
+		options.Polarity = solver.RANDOM_POLARITY
+	case "jw":
+		options.Polarity = solver.JEROSLOW_WANG_POLARITY
+	default:
+		return options, handler.Throw("Unknown polarity: "+cCtx.String("polarity"), nil)
+	}
+
+	switch cCtx.String("restart") {
+	case "none":
+		options.Restart = solver.NO_RESTART
+	case "luby":
+		options.Restart = solver.LUBY_RESTART
+	case "geometric":
+		options.Restart = solver.GEOMETRIC_RESTART
+	case "glucose":
+		options.Restart = solver.GLUCOSE_RESTART
+	default:
+		return options, handler.Throw("Unknown restart policy: "+cCtx.String("restart"), nil)
+	}
+
+	return options, nil
+}
+
+// Returns the context of the search, which SIGINT, SIGTERM and the --timeout flag cancel
+func searchContext(cCtx *cli.Context) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	if timeout := cCtx.Duration("timeout"); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		return ctx, func() {
+			cancel()
+			stop()
+		}
+	}
+	return ctx, stop
 }
 
 // Run CLI application which reads SAT file from standard input pipe and returns solution
@@ -93,6 +297,19 @@ func main() {
 				Usage:    "Switches on detailed logging for cdcl solver",
 				Required: false,
 			},
+			&cli.StringFlag{
+				Name:     "output",
+				Aliases:  []string{"o"},
+				Value:    "competition",
+				Usage:    "format of the result: competition, plain or json",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "strict",
+				Value:    false,
+				Usage:    "reject input whose clauses do not match its p cnf header",
+				Required: false,
+			},
 			&cli.BoolFlag{
 				Name:     "experimental",
 				Aliases:  []string{"e"},
@@ -100,6 +317,155 @@ func main() {
 				Usage:    "use experimental features",
 				Required: false,
 			},
+			&cli.StringFlag{
+				Name:     "formula",
+				Value:    "watched",
+				Usage:    "formula implementation used by the solver: watched or base",
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "brancher",
+				Value:    "vsids",
+				Usage:    "decision heuristic used by the solver: vsids, vmtf or random",
+				Required: false,
+			},
+			&cli.Float64Flag{
+				Name:     "decay",
+				Value:    0.95,
+				Usage:    "activity decay factor of the vsids decision heuristic",
+				Required: false,
+			},
+			&cli.Int64Flag{
+				Name:     "seed",
+				Value:    0,
+				Usage:    "seed for the random choices made by the solver",
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "polarity",
+				Value:    "false",
+				Usage:    "default polarity of decision literals: false, true, random or jw (Jeroslow-Wang)",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "phase-saving",
+				Value:    true,
+				Usage:    "reuse the last value of an atom when deciding it",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "target-phase",
+				Value:    true,
+				Usage:    "prefer the assignment of the largest conflict free trail when deciding",
+				Required: false,
+			},
+			&cli.UintFlag{
+				Name:     "rephase",
+				Value:    1000,
+				Usage:    "no of conflicts between resetting the saved phases, 0 disables rephasing",
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "restart",
+				Value:    "glucose",
+				Usage:    "restart policy of the solver: luby, glucose, geometric or none",
+				Required: false,
+			},
+			&cli.UintFlag{
+				Name:     "restart-base",
+				Value:    100,
+				Usage:    "no of conflicts before the first luby or geometric restart",
+				Required: false,
+			},
+			&cli.Float64Flag{
+				Name:     "restart-grow",
+				Value:    1.5,
+				Usage:    "factor by which geometric restart intervals grow",
+				Required: false,
+			},
+			&cli.UintFlag{
+				Name:     "reduce",
+				Value:    2000,
+				Usage:    "no of conflicts before the first deletion of learnt clauses, 0 disables deletion",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "minimize",
+				Value:    true,
+				Usage:    "remove literals implied by the rest of a learnt clause",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "binary-minimize",
+				Value:    true,
+				Usage:    "remove literals of a learnt clause using binary clauses",
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "proof",
+				Value:    "",
+				Usage:    "file to write the DRAT proof of unsatisfiability to",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "binary-proof",
+				Value:    false,
+				Usage:    "write the DRAT proof in the binary encoding",
+				Required: false,
+			},
+			&cli.DurationFlag{
+				Name:     "timeout",
+				Value:    0,
+				Usage:    "time after which the search stops with an unknown result, e.g. 30s",
+				Required: false,
+			},
+			&cli.UintFlag{
+				Name:     "conflict-limit",
+				Value:    0,
+				Usage:    "no of conflicts after which the search stops with an unknown result",
+				Required: false,
+			},
+			&cli.UintFlag{
+				Name:     "decision-limit",
+				Value:    0,
+				Usage:    "no of decisions after which the search stops with an unknown result",
+				Required: false,
+			},
+			&cli.DurationFlag{
+				Name:     "progress",
+				Value:    0,
+				Usage:    "time between progress lines of the search, e.g. 5s, no progress lines are printed if 0",
+				Required: false,
+			},
+			&cli.StringFlag{
+				Name:     "core",
+				Value:    "",
+				Usage:    "file to write the clauses of an unsatisfiable core to, in DIMCAS format",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "maxsat",
+				Value:    false,
+				Usage:    "solve a weighted partial MaxSAT problem in WCNF format, files ending in .wcnf always are",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "pb",
+				Value:    false,
+				Usage:    "solve a pseudo-Boolean problem in OPB format, files ending in .opb always are",
+				Required: false,
+			},
+			&cli.BoolFlag{
+				Name:     "mus",
+				Value:    false,
+				Usage:    "shrink the unsatisfiable core to a minimal unsatisfiable subset",
+				Required: false,
+			},
+		},
+		Commands: []*cli.Command{
+			checkProofCommand,
+			verifyModelCommand,
+			convertCommand,
 		},
 		Action: solve,
 	})
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	reader "github.com/alanpjohn/go-cdcl/pkg/io"
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+	maxsat "github.com/alanpjohn/go-cdcl/pkg/maxsat"
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+Solves a weighted partial MaxSAT problem in WCNF format read from the file or stdin
+
+The result follows the MaxSAT Evaluation: every better model found prints an `o` line with its cost in the
+competition output, and the exit code is 30 once the optimum is found.
+*/
+func solveMaxSAT(cCtx *cli.Context, format reader.OutputFormat, mode reader.ParseMode) error {
+	if cCtx.String("proof") != "" || cCtx.String("core") != "" {
+		return handler.Throw("Proofs and cores are not available for MaxSAT problems", nil)
+	}
+
+	var (
+		wcnf types.WCNFFile
+		err  error
+	)
+	if filename := cCtx.String("file"); filename != "" {
+		if wcnf, err = reader.ReadWCNFFile(filename, mode); err != nil {
+			return err
+		}
+	} else if wcnf, err = reader.ParseWCNF(os.Stdin, mode); err != nil {
+		return err
+	}
+
+	options, err := solverOptions(cCtx, format)
+	if err != nil {
+		return err
+	}
+
+	m, err := maxsat.InitializeMaxSATSolver(wcnf, options)
+	if err != nil {
+		return err
+	}
+	if format == reader.COMPETITION_OUTPUT {
+		m.Improved = func(cost uint64) {
+			fmt.Printf("o %v\n", cost)
+		}
+	}
+	logger.Info("MaxSAT solver initialized")
+
+	ctx, stop := searchContext(cCtx)
+	defer stop()
+
+	solution, err := m.Solve(ctx)
+	if m.Model() != nil && err == nil {
+		// The model is checked against the hard clauses as they were read before it is printed
+		var violated []int
+		hard := types.SATFile{AtomCount: wcnf.AtomCount, Clauses: wcnf.Hard}
+		if violated, err = solver.VerifyModel(hard, m.Model()); err == nil && len(violated) > 0 {
+			err = handler.Throw(fmt.Sprintf("Model violates hard clause %v", wcnf.Hard[violated[0]]), nil)
+		}
+	}
+
+	stats := m.SAT.Statistics()
+	cost := int64(m.Cost)
+	result := reader.Result{
+		Solution: solution,
+		Model:    m.Model(),
+		Comments: []string{
+			fmt.Sprintf("atoms %v hard clauses %v soft clauses %v", wcnf.AtomCount, len(wcnf.Hard), len(wcnf.Soft)),
+			fmt.Sprintf("lower bound %v", m.LowerBound),
+		},
+		Stats:    &stats,
+		Problem:  reader.MAXSAT_PROBLEM,
+		Cost:     &cost,
+		Streamed: format == reader.COMPETITION_OUTPUT, // Improved printed the `o` lines
+	}
+	if reason := m.SAT.Stopped(); reason != solver.NOT_STOPPED {
+		result.Comments = append(result.Comments, "search stopped: "+reason.String())
+	}
+	if version != "" {
+		result.Comments = append([]string{"gocdcl " + version}, result.Comments...)
+	}
+	if err != nil {
+		result.Solution = types.UNKNOWN
+		result.Comments = append(result.Comments, "ERROR: "+err.Error())
+	}
+
+	if err = reader.WriteResult(os.Stdout, format, result); err != nil {
+		return err
+	}
+
+	// Exit codes follow the MaxSAT Evaluation: 30 for OPTIMUM, 20 for UNSATISFIABLE, 10 for a model which may not be optimal
+	if code := reader.ExitCode(result.Solution); code != 0 {
+		return cli.Exit("", code)
+	}
+	return nil
+}
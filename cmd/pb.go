@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	reader "github.com/alanpjohn/go-cdcl/pkg/io"
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+Solves a pseudo-Boolean problem in OPB format read from the file or stdin
+
+The result follows the pseudo-Boolean competition: every better model of an objective prints an `o` line
+with its cost in the competition output, and the exit code is 30 once the optimum is found.
+*/
+func solvePB(cCtx *cli.Context, format reader.OutputFormat, mode reader.ParseMode) error {
+	if cCtx.String("proof") != "" || cCtx.String("core") != "" {
+		return handler.Throw("Proofs and cores are not available for pseudo-Boolean problems", nil)
+	}
+
+	var (
+		opb types.OPBFile
+		err error
+	)
+	if filename := cCtx.String("file"); filename != "" {
+		if opb, err = reader.ReadOPBFile(filename, mode); err != nil {
+			return err
+		}
+	} else if opb, err = reader.ParseOPB(os.Stdin, mode); err != nil {
+		return err
+	}
+
+	options, err := solverOptions(cCtx, format)
+	if err != nil {
+		return err
+	}
+
+	base, err := solver.InitializeBaseSolver(types.SATFile{AtomCount: opb.AtomCount}, options)
+	if err != nil {
+		return err
+	}
+	for _, c := range opb.Constraints {
+		if err = base.AddPB(c); err != nil {
+			return err
+		}
+	}
+	logger.Info("Solver initialized")
+
+	ctx, stop := searchContext(cCtx)
+	defer stop()
+
+	var (
+		solution types.Solution
+		cost     *int64
+	)
+	if opb.Objective != nil {
+		var best int64
+		improved := func(c int64) {}
+		if format == reader.COMPETITION_OUTPUT {
+			improved = func(c int64) {
+				fmt.Printf("o %v\n", c)
+			}
+		}
+		solution, best, err = base.OptimizeContext(ctx, opb.Objective, improved)
+		cost = &best
+	} else {
+		solution, err = base.SolveContext(ctx)
+	}
+
+	model := base.Model()
+	if len(model) > int(opb.AtomCount) {
+		// Atoms of the bounds on the objective are not part of the input
+		model = model[:opb.AtomCount]
+	}
+	if model != nil && err == nil {
+		// The model is checked against the constraints as they were read before it is printed
+		if violated := solver.VerifyPB(opb.Constraints, model); len(violated) > 0 {
+			err = handler.Throw(fmt.Sprintf("Model violates constraint %v", opb.Constraints[violated[0]]), nil)
+		}
+	}
+
+	stats := base.Statistics()
+	result := reader.Result{
+		Solution: solution,
+		Model:    model,
+		Comments: []string{
+			fmt.Sprintf("atoms %v constraints %v", opb.AtomCount, len(opb.Constraints)),
+		},
+		Stats:    &stats,
+		Problem:  reader.PB_PROBLEM,
+		Cost:     cost,
+		Streamed: format == reader.COMPETITION_OUTPUT, // improved printed the `o` lines
+	}
+	if reason := base.Stopped(); reason != solver.NOT_STOPPED {
+		result.Comments = append(result.Comments, "search stopped: "+reason.String())
+	}
+	if version != "" {
+		result.Comments = append([]string{"gocdcl " + version}, result.Comments...)
+	}
+	if err != nil {
+		result.Solution = types.UNKNOWN
+		result.Comments = append(result.Comments, "ERROR: "+err.Error())
+	}
+
+	if err = reader.WriteResult(os.Stdout, format, result); err != nil {
+		return err
+	}
+
+	// Exit codes follow the pseudo-Boolean competition: 30 for OPTIMUM, 20 for UNSATISFIABLE and 10 for a model
+	if code := reader.ExitCode(result.Solution); code != 0 {
+		return cli.Exit("", code)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	reader "github.com/alanpjohn/go-cdcl/pkg/io"
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+Checks a model given in `v` lines against a DIMCAS file and reports every violated clause
+
+The verify-model command
+*/
+func verifyModel(cCtx *cli.Context) error {
+	logger.Verbosity = cCtx.Bool("verbose")
+
+	if cCtx.NArg() != 2 {
+		return handler.Throw("Expected a DIMCAS file and a model file", nil)
+	}
+
+	sat, err := reader.ReadFile(cCtx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	modelFile, err := os.Open(cCtx.Args().Get(1))
+	if err != nil {
+		return handler.Throw("The model file could not be opened", err)
+	}
+	defer modelFile.Close()
+
+	model, err := reader.ReadModel(modelFile)
+	if err != nil {
+		return err
+	}
+
+	violated, err := solver.VerifyModel(sat, model)
+	if err != nil {
+		fmt.Println("c " + err.Error())
+		fmt.Println("s NOT VERIFIED")
+		return cli.Exit("", 1)
+	}
+
+	for _, i := range violated {
+		fmt.Println("c violated " + violation(sat, i))
+	}
+
+	if len(violated) > 0 {
+		fmt.Printf("c %v of %v clauses violated\n", len(violated), len(sat.Clauses)+len(sat.Cardinalities)+len(sat.XORs))
+		fmt.Println("s NOT VERIFIED")
+		return cli.Exit("", 1)
+	}
+
+	fmt.Println("s VERIFIED")
+	return nil
+}
+
+/*
+Describes the clause or constraint of the SATFile with the given index in DIMCAS, as numbered by
+solver.VerifyModel
+*/
+func violation(sat types.SATFile, i int) string {
+	var (
+		kind   = "clause"
+		prefix string
+		d      types.Disjunction
+		n      = i
+	)
+	switch {
+	case n < len(sat.Clauses):
+		d = sat.Clauses[n]
+	case n-len(sat.Clauses) < len(sat.Cardinalities):
+		n -= len(sat.Clauses)
+		c := sat.Cardinalities[n]
+		kind, prefix, d = "cardinality constraint", fmt.Sprintf("k %v ", c.Bound), c.Literals
+	default:
+		n -= len(sat.Clauses) + len(sat.Cardinalities)
+		kind, prefix, d = "XOR constraint", "x", sat.XORs[n].Literals
+	}
+
+	out := fmt.Sprintf("%v %v: %v", kind, n+1, prefix)
+	for _, l := range d {
+		out += fmt.Sprintf("%v ", l)
+	}
+	return out + "0"
+}
+
+// Command checking models
+var verifyModelCommand = &cli.Command{
+	Name:      "verify-model",
+	Usage:     "Check a model given in v lines against a DIMCAS file",
+	ArgsUsage: "<dimcas file> <model file>",
+	Action:    verifyModel,
+}
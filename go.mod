@@ -2,7 +2,10 @@ module github.com/alanpjohn/go-cdcl
 
 go 1.20
 
-require github.com/urfave/cli/v2 v2.25.0
+require (
+	github.com/ulikunitz/xz v0.5.15
+	github.com/urfave/cli/v2 v2.25.0
+)
 
 require (
 	github.com/cpuguy83/go-md2man/v2 v2.0.2 // indirect
@@ -0,0 +1,224 @@
+package encode
+
+/*
+The cardinality file encodes that at most k of n distinct literals are true, for 0 < k < n.
+Each encoding counts the true literals in fresh atoms and refutes the count of k+1. The counts are
+only implied upwards, by the literals, which is all an at most constraint needs.
+*/
+
+import (
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+// Adds a clause refuting every k+1 of the literals
+func pairwise(literals []types.Literal, k int) []types.Disjunction {
+	var (
+		clauses []types.Disjunction
+		chosen  = make(types.Disjunction, 0, k+1)
+		choose  func(from int)
+	)
+	choose = func(from int) {
+		if len(chosen) == k+1 {
+			clauses = append(clauses, append(types.Disjunction{}, chosen...))
+			return
+		}
+		// Enough literals have to be left to fill the clause
+		for i := from; i <= len(literals)-(k+1-len(chosen)); i++ {
+			chosen = append(chosen, literals[i].Negate())
+			choose(i + 1)
+			chosen = chosen[:len(chosen)-1]
+		}
+	}
+	choose(0)
+	return clauses
+}
+
+/*
+Sequential counter of Sinz. The register s[i][j] holds if at least j+1 of the first i+1 literals are
+true, a literal is refuted when the register before it already counts k.
+*/
+func (e *Encoder) sequential(literals []types.Literal, k int) []types.Disjunction {
+	n := len(literals)
+	s := make([][]types.Literal, n-1)
+	for i := range s {
+		s[i] = make([]types.Literal, k)
+		for j := range s[i] {
+			s[i][j] = e.Fresh()
+		}
+	}
+
+	clauses := []types.Disjunction{{literals[0].Negate(), s[0][0]}}
+	for j := 1; j < k; j++ {
+		clauses = append(clauses, types.Disjunction{s[0][j].Negate()})
+	}
+	for i := 1; i < n-1; i++ {
+		x := literals[i]
+		clauses = append(clauses,
+			types.Disjunction{x.Negate(), s[i][0]},
+			types.Disjunction{s[i-1][0].Negate(), s[i][0]},
+		)
+		for j := 1; j < k; j++ {
+			clauses = append(clauses,
+				types.Disjunction{x.Negate(), s[i-1][j-1].Negate(), s[i][j]},
+				types.Disjunction{s[i-1][j].Negate(), s[i][j]},
+			)
+		}
+		clauses = append(clauses, types.Disjunction{x.Negate(), s[i-1][k-1].Negate()})
+	}
+	clauses = append(clauses, types.Disjunction{literals[n-1].Negate(), s[n-2][k-1].Negate()})
+
+	return clauses
+}
+
+/*
+Totalizer of Bailleux and Boufkhad. Every node of a balanced tree over the literals has unary outputs,
+output j holds if at least j+1 literals below the node are true. Outputs beyond k+1 are not needed,
+so each node has at most k+1 of them.
+*/
+func (e *Encoder) totalizer(literals []types.Literal, k int) []types.Disjunction {
+	var clauses []types.Disjunction
+
+	var build func(literals []types.Literal) []types.Literal
+	build = func(literals []types.Literal) []types.Literal {
+		if len(literals) == 1 {
+			return literals
+		}
+		left, right := build(literals[:len(literals)/2]), build(literals[len(literals)/2:])
+
+		size := len(left) + len(right)
+		if size > k+1 {
+			size = k + 1
+		}
+		outputs := make([]types.Literal, size)
+		for i := range outputs {
+			outputs[i] = e.Fresh()
+		}
+
+		// i true literals on the left and j on the right are at least i+j true literals
+		for i := 0; i <= len(left); i++ {
+			for j := 0; j <= len(right); j++ {
+				if i+j == 0 {
+					continue
+				}
+				sum := i + j
+				if sum > size {
+					sum = size
+				}
+				clause := types.Disjunction{outputs[sum-1]}
+				if i > 0 {
+					clause = append(clause, left[i-1].Negate())
+				}
+				if j > 0 {
+					clause = append(clause, right[j-1].Negate())
+				}
+				clauses = append(clauses, clause)
+			}
+		}
+		return outputs
+	}
+
+	outputs := build(literals)
+	return append(clauses, types.Disjunction{outputs[k].Negate()})
+}
+
+/*
+Cardinality network of Asín et al. The literals are split into blocks of m, the least power of two above
+k, which are sorted in descending order by odd-even merge sorting networks. The sorted blocks are then
+merged one after another by simplified merges, of which only the first m outputs are kept, so the
+encoding has O(n log^2 k) clauses. Comparators only imply their outputs upwards, output k holds if more
+than k literals are true and is refuted. The literals are padded to a multiple of m with a refuted atom.
+*/
+func (e *Encoder) network(literals []types.Literal, k int) []types.Disjunction {
+	var clauses []types.Disjunction
+
+	m := 1
+	for m <= k {
+		m *= 2
+	}
+	inputs := append([]types.Literal{}, literals...)
+	if len(inputs)%m != 0 {
+		padding := e.Fresh()
+		clauses = append(clauses, types.Disjunction{padding.Negate()})
+		for len(inputs)%m != 0 {
+			inputs = append(inputs, padding)
+		}
+	}
+
+	// The larger of the two literals comes first, it holds if either does and the smaller if both do
+	comparator := func(a, b types.Literal) (types.Literal, types.Literal) {
+		high, low := e.Fresh(), e.Fresh()
+		clauses = append(clauses,
+			types.Disjunction{a.Negate(), high},
+			types.Disjunction{b.Negate(), high},
+			types.Disjunction{a.Negate(), b.Negate(), low},
+		)
+		return high, low
+	}
+
+	// Splits a sequence into the literals at even and odd positions
+	split := func(literals []types.Literal) (even, odd []types.Literal) {
+		for i, l := range literals {
+			if i%2 == 0 {
+				even = append(even, l)
+			} else {
+				odd = append(odd, l)
+			}
+		}
+		return even, odd
+	}
+
+	// Merges two sorted sequences of the same length, a power of two
+	var merge func(a, b []types.Literal) []types.Literal
+	merge = func(a, b []types.Literal) []types.Literal {
+		if len(a) == 1 {
+			high, low := comparator(a[0], b[0])
+			return []types.Literal{high, low}
+		}
+		aEven, aOdd := split(a)
+		bEven, bOdd := split(b)
+		even, odd := merge(aEven, bEven), merge(aOdd, bOdd)
+
+		out := make([]types.Literal, 0, 2*len(a))
+		out = append(out, even[0])
+		for i := 0; i < len(a)-1; i++ {
+			high, low := comparator(odd[i], even[i+1])
+			out = append(out, high, low)
+		}
+		return append(out, odd[len(a)-1])
+	}
+
+	// Merges like merge, but only the first len(a)+1 outputs are sorted
+	var simplifiedMerge func(a, b []types.Literal) []types.Literal
+	simplifiedMerge = func(a, b []types.Literal) []types.Literal {
+		if len(a) == 1 {
+			high, low := comparator(a[0], b[0])
+			return []types.Literal{high, low}
+		}
+		aEven, aOdd := split(a)
+		bEven, bOdd := split(b)
+		even, odd := simplifiedMerge(aEven, bEven), simplifiedMerge(aOdd, bOdd)
+
+		out := make([]types.Literal, 0, len(a)+1)
+		out = append(out, even[0])
+		for i := 0; i < len(a)/2; i++ {
+			high, low := comparator(even[i+1], odd[i])
+			out = append(out, high, low)
+		}
+		return out
+	}
+
+	var sort func(literals []types.Literal) []types.Literal
+	sort = func(literals []types.Literal) []types.Literal {
+		if len(literals) == 1 {
+			return literals
+		}
+		half := len(literals) / 2
+		return merge(sort(literals[:half]), sort(literals[half:]))
+	}
+
+	outputs := sort(inputs[:m])
+	for i := m; i < len(inputs); i += m {
+		outputs = simplifiedMerge(outputs, sort(inputs[i:i+m]))[:m]
+	}
+	return append(clauses, types.Disjunction{outputs[k].Negate()})
+}
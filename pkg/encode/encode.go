@@ -0,0 +1,253 @@
+package encode
+
+/*
+The encode package turns cardinality and pseudo-Boolean constraints into clauses, for formulas which
+have to stay in pure CNF. The solver propagates these constraints natively, the clauses are meant for
+other solvers and for comparing encodings.
+
+Encodings other than the pairwise one introduce fresh atoms, which the Encoder numbers after the atoms
+of the formula. The fresh atoms only serve the encoding, every model of the original atoms which
+satisfies a constraint extends to a model of its clauses and no other model does.
+*/
+
+import (
+	"fmt"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+Encoding is an enum defining how constraints are turned into clauses.
+
+The first four encode cardinality constraints, pseudo-Boolean constraints with equal coefficients are
+encoded by them as cardinality constraints. The last two encode pseudo-Boolean constraints and
+cardinality constraints alike.
+*/
+type Encoding uint
+
+const (
+	PAIRWISE_ENCODING   Encoding = iota // A clause for every k+1 literals, no fresh atoms but exponential in k
+	SEQUENTIAL_ENCODING                 // Sequential counter of Sinz, O(n·k) clauses
+	TOTALIZER_ENCODING                  // Totalizer of Bailleux and Boufkhad, unary sums merged along a tree
+	NETWORK_ENCODING                    // Cardinality network, odd-even merge sorting truncated to k+1 outputs
+	BDD_ENCODING                        // Binary decision diagram of the constraint
+	ADDER_ENCODING                      // Binary adders summing the coefficients, compared with the degree
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case PAIRWISE_ENCODING:
+		return "pairwise"
+	case SEQUENTIAL_ENCODING:
+		return "sequential"
+	case TOTALIZER_ENCODING:
+		return "totalizer"
+	case NETWORK_ENCODING:
+		return "network"
+	case BDD_ENCODING:
+		return "bdd"
+	case ADDER_ENCODING:
+		return "adder"
+	}
+	return "unknown"
+}
+
+// Parses the name of an Encoding as given on the command line
+func ParseEncoding(name string) (Encoding, error) {
+	for e := PAIRWISE_ENCODING; e <= ADDER_ENCODING; e++ {
+		if e.String() == name {
+			return e, nil
+		}
+	}
+	return PAIRWISE_ENCODING, handler.Throw("Unknown encoding: "+name, nil)
+}
+
+/*
+Encoder encodes constraints with an Encoding. Fresh atoms are numbered after AtomCount, which grows
+with every fresh atom and is the atom count of the formula including the encodings.
+*/
+type Encoder struct {
+	Encoding  Encoding
+	AtomCount uint // No of atoms used, fresh atoms are numbered after it
+}
+
+// Constructs an Encoder whose fresh atoms are numbered after the atoms of the SATFile
+func ConstructEncoder(sat types.SATFile, encoding Encoding) *Encoder {
+	return &Encoder{Encoding: encoding, AtomCount: sat.AtomCount}
+}
+
+// Fresh returns the positive literal of a new atom
+func (e *Encoder) Fresh() types.Literal {
+	e.AtomCount++
+	return types.Literal(e.AtomCount)
+}
+
+// Notes the atoms of the literals as used, so that fresh atoms are numbered after them
+func (e *Encoder) use(literals []types.Literal) {
+	for _, l := range literals {
+		if uint(l.Atom()) > e.AtomCount {
+			e.AtomCount = uint(l.Atom())
+		}
+	}
+}
+
+// Removes repeated literals, a literal counts once towards the bound of a constraint
+func distinct(literals []types.Literal) []types.Literal {
+	seen := make(map[types.Literal]bool, len(literals))
+	var out []types.Literal
+	for _, l := range literals {
+		if !seen[l] {
+			seen[l] = true
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func negate(literals []types.Literal) []types.Literal {
+	out := make([]types.Literal, len(literals))
+	for i, l := range literals {
+		out[i] = l.Negate()
+	}
+	return out
+}
+
+/*
+AtMost encodes that at most k of the literals are true.
+
+The constraint is trivial if k is at least the no of distinct literals and no clauses are returned,
+with k of 0 every literal is refuted by a unit clause.
+*/
+func (e *Encoder) AtMost(literals []types.Literal, k uint) ([]types.Disjunction, error) {
+	for _, l := range literals {
+		if l == 0 {
+			return nil, handler.Throw("Cardinality constraint contains 0, which is not a literal", nil)
+		}
+	}
+	literals = distinct(literals)
+	e.use(literals)
+
+	switch {
+	case k >= uint(len(literals)):
+		return nil, nil
+	case k == 0:
+		clauses := make([]types.Disjunction, len(literals))
+		for i, l := range literals {
+			clauses[i] = types.Disjunction{l.Negate()}
+		}
+		return clauses, nil
+	}
+
+	switch e.Encoding {
+	case PAIRWISE_ENCODING:
+		return pairwise(literals, int(k)), nil
+	case SEQUENTIAL_ENCODING:
+		return e.sequential(literals, int(k)), nil
+	case TOTALIZER_ENCODING:
+		return e.totalizer(literals, int(k)), nil
+	case NETWORK_ENCODING:
+		return e.network(literals, int(k)), nil
+	case BDD_ENCODING, ADDER_ENCODING:
+		terms := make([]types.PBTerm, len(literals))
+		for i, l := range literals {
+			terms[i] = types.PBTerm{Coefficient: 1, Literal: l.Negate()}
+		}
+		return e.PB(types.PBConstraint{Terms: terms, Degree: int64(len(literals)) - int64(k)})
+	}
+	return nil, handler.Throw(fmt.Sprintf("Unknown encoding %v", e.Encoding), nil)
+}
+
+// AtLeast encodes that at least k of the literals are true, which is at most n-k of their negations
+func (e *Encoder) AtLeast(literals []types.Literal, k uint) ([]types.Disjunction, error) {
+	literals = distinct(literals)
+	if k > uint(len(literals)) {
+		// The constraint never holds
+		e.use(literals)
+		return []types.Disjunction{{}}, nil
+	}
+	return e.AtMost(negate(literals), uint(len(literals))-k)
+}
+
+// Exactly encodes that exactly k of the literals are true, as at most k and at least k of them
+func (e *Encoder) Exactly(literals []types.Literal, k uint) ([]types.Disjunction, error) {
+	atMost, err := e.AtMost(literals, k)
+	if err != nil {
+		return nil, err
+	}
+	atLeast, err := e.AtLeast(literals, k)
+	if err != nil {
+		return nil, err
+	}
+	return append(atMost, atLeast...), nil
+}
+
+// Cardinality encodes a Cardinality, at least Bound of its literals are true
+func (e *Encoder) Cardinality(c types.Cardinality) ([]types.Disjunction, error) {
+	return e.AtLeast(c.Literals, c.Bound)
+}
+
+/*
+PB encodes a pseudo-Boolean constraint after normalizing it, refer solver.NormalizePB.
+
+With a cardinality Encoding the normalized constraint must have equal coefficients, it is then
+encoded as a cardinality constraint.
+*/
+func (e *Encoder) PB(c types.PBConstraint) ([]types.Disjunction, error) {
+	pb, total, err := solver.NormalizePB(c)
+	if err != nil {
+		return nil, err
+	}
+
+	literals := make([]types.Literal, len(pb.Terms))
+	for i, t := range pb.Terms {
+		literals[i] = t.Literal
+	}
+	e.use(literals)
+
+	switch {
+	case pb.Degree <= 0:
+		return nil, nil
+	case total < pb.Degree:
+		return []types.Disjunction{{}}, nil
+	}
+
+	switch e.Encoding {
+	case BDD_ENCODING:
+		return e.bdd(pb), nil
+	case ADDER_ENCODING:
+		return e.adder(pb), nil
+	}
+
+	// Coefficients are sorted in descending order, so the first and the last are equal if all are
+	first, last := pb.Terms[0].Coefficient, pb.Terms[len(pb.Terms)-1].Coefficient
+	if first != last {
+		return nil, handler.Throw(fmt.Sprintf("The %v encoding only encodes pseudo-Boolean constraints with equal coefficients", e.Encoding), nil)
+	}
+	return e.AtLeast(literals, uint((pb.Degree+first-1)/first))
+}
+
+/*
+Encode returns a copy of the SATFile where the cardinality constraints are replaced by their clauses
+in the given Encoding, with the fresh atoms added to the atom count. XOR constraints are kept.
+*/
+func Encode(sat types.SATFile, encoding Encoding) (types.SATFile, error) {
+	e := ConstructEncoder(sat, encoding)
+	encoded := types.SATFile{Clauses: append([]types.Disjunction{}, sat.Clauses...), XORs: sat.XORs}
+	for _, d := range sat.Clauses {
+		e.use(d)
+	}
+
+	for _, c := range sat.Cardinalities {
+		clauses, err := e.Cardinality(c)
+		if err != nil {
+			return sat, err
+		}
+		encoded.Clauses = append(encoded.Clauses, clauses...)
+	}
+
+	encoded.AtomCount = e.AtomCount
+	encoded.ClauseCount = uint(len(encoded.Clauses))
+	return encoded, nil
+}
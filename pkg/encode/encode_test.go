@@ -0,0 +1,169 @@
+package encode_test
+
+import (
+	"math/rand"
+	"testing"
+
+	encode "github.com/alanpjohn/go-cdcl/pkg/encode"
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+var encodings = []encode.Encoding{
+	encode.PAIRWISE_ENCODING, encode.SEQUENTIAL_ENCODING, encode.TOTALIZER_ENCODING,
+	encode.NETWORK_ENCODING, encode.BDD_ENCODING, encode.ADDER_ENCODING,
+}
+
+/*
+Checks that the clauses have a model extending an assignment of the first atoms exactly when holds
+accepts the assignment, for every assignment
+*/
+func checkEncoding(t *testing.T, atoms int, e *encode.Encoder, clauses []types.Disjunction, holds func(model []types.Literal) bool) {
+	t.Helper()
+	s, err := solver.InitializeBaseSolver(types.SATFile{AtomCount: e.AtomCount, Clauses: clauses}, solver.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	model := make([]types.Literal, atoms)
+	for bits := 0; bits < 1<<atoms; bits++ {
+		for i := range model {
+			model[i] = types.Literal(i + 1)
+			if bits&(1<<i) == 0 {
+				model[i] = model[i].Negate()
+			}
+		}
+		solution, err := s.Solve(model...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := holds(model); expected != (solution == types.SATISFIABLE) {
+			t.Fatalf("%v encoding: expected satisfiable %v under %v, found %v", e.Encoding, expected, model, solution)
+		}
+	}
+}
+
+func TestCardinalityEncodings(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+
+	for n := 0; n < 40; n++ {
+		atoms := 2 + random.Intn(5)
+		var literals []types.Literal
+		for i := 1 + random.Intn(7); i > 0; i-- {
+			l := types.Literal(1 + random.Intn(atoms))
+			if random.Intn(2) == 0 {
+				l = l.Negate()
+			}
+			literals = append(literals, l)
+		}
+		k := uint(random.Intn(len(literals) + 1))
+		sat := types.SATFile{AtomCount: uint(atoms), Cardinalities: []types.Cardinality{{Literals: literals, Bound: k}}}
+
+		count := func(model []types.Literal) uint {
+			var c uint
+			seen := make(map[types.Literal]bool)
+			for _, l := range literals {
+				if !seen[l] && model[l.Atom()-1] == l {
+					c++
+				}
+				seen[l] = true
+			}
+			return c
+		}
+
+		for _, encoding := range encodings {
+			e := encode.ConstructEncoder(sat, encoding)
+			atMost, err := e.AtMost(literals, k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkEncoding(t, atoms, e, atMost, func(model []types.Literal) bool { return count(model) <= k })
+
+			e = encode.ConstructEncoder(sat, encoding)
+			exactly, err := e.Exactly(literals, k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkEncoding(t, atoms, e, exactly, func(model []types.Literal) bool { return count(model) == k })
+
+			encoded, err := encode.Encode(sat, encoding)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(encoded.Cardinalities) != 0 || encoded.AtomCount < sat.AtomCount {
+				t.Fatalf("%v encoding: expected only clauses, found %+v", encoding, encoded)
+			}
+			checkEncoding(t, atoms, &encode.Encoder{AtomCount: encoded.AtomCount}, encoded.Clauses, func(model []types.Literal) bool { return count(model) >= k })
+		}
+	}
+}
+
+func TestCardinalityNetwork(t *testing.T) {
+	// Small bounds split the literals into several blocks joined by truncated merges
+	const atoms = 11
+	literals := make([]types.Literal, atoms)
+	for i := range literals {
+		literals[i] = types.Literal(i + 1)
+	}
+	sat := types.SATFile{AtomCount: atoms}
+
+	for k := uint(1); k < 6; k++ {
+		e := encode.ConstructEncoder(sat, encode.NETWORK_ENCODING)
+		atMost, err := e.AtMost(literals, k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkEncoding(t, atoms, e, atMost, func(model []types.Literal) bool {
+			var count uint
+			for _, l := range model {
+				if l > 0 {
+					count++
+				}
+			}
+			return count <= k
+		})
+	}
+}
+
+func TestPBEncodings(t *testing.T) {
+	random := rand.New(rand.NewSource(4))
+
+	for n := 0; n < 60; n++ {
+		atoms := 2 + random.Intn(5)
+		var c types.PBConstraint
+		for i := 1 + random.Intn(6); i > 0; i-- {
+			l := types.Literal(1 + random.Intn(atoms))
+			if random.Intn(2) == 0 {
+				l = l.Negate()
+			}
+			c.Terms = append(c.Terms, types.PBTerm{Coefficient: int64(random.Intn(13) - 4), Literal: l})
+		}
+		c.Degree = int64(random.Intn(12) - 2)
+
+		for _, encoding := range encodings {
+			e := &encode.Encoder{Encoding: encoding, AtomCount: uint(atoms)}
+			clauses, err := e.PB(c)
+			if err != nil {
+				if encoding == encode.BDD_ENCODING || encoding == encode.ADDER_ENCODING {
+					t.Fatal(err)
+				}
+				// Cardinality encodings only take constraints with equal coefficients
+				continue
+			}
+			checkEncoding(t, atoms, e, clauses, func(model []types.Literal) bool {
+				return len(solver.VerifyPB([]types.PBConstraint{c}, model)) == 0
+			})
+		}
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	for _, encoding := range encodings {
+		if parsed, err := encode.ParseEncoding(encoding.String()); err != nil || parsed != encoding {
+			t.Errorf("Expected %v, found %v %v", encoding, parsed, err)
+		}
+	}
+	if _, err := encode.ParseEncoding("unary"); err == nil {
+		t.Errorf("Expected an error for an unknown encoding")
+	}
+}
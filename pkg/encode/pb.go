@@ -0,0 +1,179 @@
+package encode
+
+/*
+The pb file encodes normalized pseudo-Boolean constraints, with positive coefficients sorted in
+descending order and 0 < degree ≤ sum of the coefficients.
+*/
+
+import (
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+// Key of a node of the BDD, the terms left and the degree they still have to reach
+type bddNode struct {
+	term   int
+	degree int64
+}
+
+/*
+Binary decision diagram of Eén and Sörensson. The node of term i and degree d holds if the terms
+from i onwards reach d, it branches on the literal of term i. Only the implications from a node to
+its children are encoded, which suffices as the child taking the literal needs a lower degree.
+Nodes are shared by their term and degree.
+*/
+func (e *Encoder) bdd(pb types.PBConstraint) []types.Disjunction {
+	var clauses []types.Disjunction
+
+	rest := make([]int64, len(pb.Terms)+1) // rest[i] is the sum of the coefficients from term i
+	for i := len(pb.Terms) - 1; i >= 0; i-- {
+		rest[i] = rest[i+1] + pb.Terms[i].Coefficient
+	}
+
+	const (
+		TRUE  types.Literal = -1 // The node always holds
+		FALSE types.Literal = -2 // The node never holds
+	)
+	nodes := make(map[bddNode]types.Literal)
+
+	var build func(term int, degree int64) types.Literal
+	build = func(term int, degree int64) types.Literal {
+		switch {
+		case degree <= 0:
+			return TRUE
+		case rest[term] < degree:
+			return FALSE
+		}
+		key := bddNode{term: term, degree: degree}
+		if node, ok := nodes[key]; ok {
+			return node
+		}
+
+		t := pb.Terms[term]
+		high, low := build(term+1, degree-t.Coefficient), build(term+1, degree)
+		node := e.Fresh()
+		// The literal leads to the high child, its negation to the low child
+		if high != TRUE {
+			clauses = append(clauses, types.Disjunction{node.Negate(), high})
+		}
+		if low == FALSE {
+			clauses = append(clauses, types.Disjunction{node.Negate(), t.Literal})
+		} else if low != TRUE {
+			clauses = append(clauses, types.Disjunction{node.Negate(), t.Literal, low})
+		}
+		nodes[key] = node
+		return node
+	}
+
+	root := build(0, pb.Degree)
+	return append(clauses, types.Disjunction{root})
+}
+
+/*
+Adder network of Eén and Sörensson. The coefficients of the true literals are summed in binary by
+full and half adders, which are encoded both ways as the bits of the sum must be exact. The bits are
+then compared with the degree.
+*/
+func (e *Encoder) adder(pb types.PBConstraint) []types.Disjunction {
+	var clauses []types.Disjunction
+
+	// buckets[b] are the bits of weight 2^b still to be summed
+	var buckets [][]types.Literal
+	for _, t := range pb.Terms {
+		for b := 0; t.Coefficient>>b > 0; b++ {
+			if t.Coefficient>>b&1 == 1 {
+				for len(buckets) <= b {
+					buckets = append(buckets, nil)
+				}
+				buckets[b] = append(buckets[b], t.Literal)
+			}
+		}
+	}
+
+	var sum []types.Literal
+	for b := 0; b < len(buckets); b++ {
+		for len(buckets[b]) > 1 {
+			var s, carry types.Literal
+			if bits := buckets[b]; len(bits) >= 3 {
+				s, carry = e.fullAdder(bits[0], bits[1], bits[2], &clauses)
+				buckets[b] = append(bits[3:], s)
+			} else {
+				s, carry = e.halfAdder(bits[0], bits[1], &clauses)
+				buckets[b] = []types.Literal{s}
+			}
+			if b+1 == len(buckets) {
+				buckets = append(buckets, nil)
+			}
+			buckets[b+1] = append(buckets[b+1], carry)
+		}
+		if len(buckets[b]) == 1 {
+			sum = append(sum, buckets[b][0])
+		} else {
+			// No bits of this weight, the bit of the sum is 0
+			zero := e.Fresh()
+			clauses = append(clauses, types.Disjunction{zero.Negate()})
+			sum = append(sum, zero)
+		}
+	}
+
+	/*
+		The sum is below the degree if at some bit the degree has a 1 and the sum a 0, while the higher
+		bits are equal. Every such case is refuted by a clause.
+	*/
+	for j := range sum {
+		if pb.Degree>>j&1 == 0 {
+			continue
+		}
+		clause := types.Disjunction{sum[j]}
+		for i := j + 1; i < len(sum); i++ {
+			if pb.Degree>>i&1 == 1 {
+				clause = append(clause, sum[i].Negate())
+			} else {
+				clause = append(clause, sum[i])
+			}
+		}
+		clauses = append(clauses, clause)
+	}
+
+	return clauses
+}
+
+// Adds the clauses of a full adder and returns the sum and carry bits of a+b+c
+func (e *Encoder) fullAdder(a, b, c types.Literal, clauses *[]types.Disjunction) (types.Literal, types.Literal) {
+	s, carry := e.Fresh(), e.Fresh()
+	*clauses = append(*clauses,
+		// s = a ⊕ b ⊕ c
+		types.Disjunction{a.Negate(), b.Negate(), c.Negate(), s},
+		types.Disjunction{a.Negate(), b, c, s},
+		types.Disjunction{a, b.Negate(), c, s},
+		types.Disjunction{a, b, c.Negate(), s},
+		types.Disjunction{a, b, c, s.Negate()},
+		types.Disjunction{a, b.Negate(), c.Negate(), s.Negate()},
+		types.Disjunction{a.Negate(), b, c.Negate(), s.Negate()},
+		types.Disjunction{a.Negate(), b.Negate(), c, s.Negate()},
+		// carry holds if at least two of a, b and c do
+		types.Disjunction{a.Negate(), b.Negate(), carry},
+		types.Disjunction{a.Negate(), c.Negate(), carry},
+		types.Disjunction{b.Negate(), c.Negate(), carry},
+		types.Disjunction{a, b, carry.Negate()},
+		types.Disjunction{a, c, carry.Negate()},
+		types.Disjunction{b, c, carry.Negate()},
+	)
+	return s, carry
+}
+
+// Adds the clauses of a half adder and returns the sum and carry bits of a+b
+func (e *Encoder) halfAdder(a, b types.Literal, clauses *[]types.Disjunction) (types.Literal, types.Literal) {
+	s, carry := e.Fresh(), e.Fresh()
+	*clauses = append(*clauses,
+		// s = a ⊕ b
+		types.Disjunction{a.Negate(), b, s},
+		types.Disjunction{a, b.Negate(), s},
+		types.Disjunction{a, b, s.Negate()},
+		types.Disjunction{a.Negate(), b.Negate(), s.Negate()},
+		// carry = a ∧ b
+		types.Disjunction{a.Negate(), b.Negate(), carry},
+		types.Disjunction{a, carry.Negate()},
+		types.Disjunction{b, carry.Negate()},
+	)
+	return s, carry
+}
@@ -0,0 +1,59 @@
+package io
+
+/*
+The compress file detects compressed input from its first bytes, so that benchmarks can be read as
+they are shipped without decompressing them first.
+*/
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+
+	"github.com/ulikunitz/xz" // Pure Go xz decoder, the standard library has none
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+)
+
+// Magic bytes starting the supported compression formats
+var (
+	GZIP_MAGIC  = []byte{0x1f, 0x8b}
+	BZIP2_MAGIC = []byte("BZh")
+	XZ_MAGIC    = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+)
+
+/*
+Decompress returns a reader of the decompressed input if the input starts with the magic bytes of
+gzip, bzip2 or xz, otherwise a reader of the input as it is.
+*/
+func Decompress(r io.Reader) (io.Reader, error) {
+	in := bufio.NewReader(r)
+	head, err := in.Peek(len(XZ_MAGIC))
+	if err != nil && err != io.EOF {
+		return nil, handler.Throw("Input could not be read", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(head, GZIP_MAGIC):
+		logger.Info("Decompressing gzip input")
+		z, err := gzip.NewReader(in)
+		if err != nil {
+			return nil, handler.Throw("Invalid gzip input: "+err.Error(), err)
+		}
+		return z, nil
+	case bytes.HasPrefix(head, BZIP2_MAGIC):
+		logger.Info("Decompressing bzip2 input")
+		return bzip2.NewReader(in), nil
+	case bytes.HasPrefix(head, XZ_MAGIC):
+		logger.Info("Decompressing xz input")
+		z, err := xz.NewReader(in)
+		if err != nil {
+			return nil, handler.Throw("Invalid xz input: "+err.Error(), err)
+		}
+		return z, nil
+	}
+	return in, nil
+}
@@ -0,0 +1,60 @@
+package io_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/ulikunitz/xz"
+
+	reader "github.com/alanpjohn/go-cdcl/pkg/io"
+)
+
+const compressedCNF = "p cnf 2 2\n1 -2 0\n2 0\n"
+
+// compressedCNF compressed by bzip2, the standard library has no bzip2 encoder
+var bzip2CNF = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x11, 0xb1, 0x1b, 0x96, 0x00, 0x00,
+	0x0a, 0x59, 0x80, 0x00, 0x10, 0x40, 0x02, 0x70, 0x00, 0x09, 0x01, 0x40, 0x00, 0x20, 0x00, 0x22,
+	0x34, 0xd0, 0x69, 0xa1, 0x00, 0x30, 0xcb, 0x8c, 0x21, 0x3d, 0x1b, 0x28, 0x98, 0x6e, 0xbc, 0x5d,
+	0xc9, 0x14, 0xe1, 0x42, 0x40, 0x46, 0xc4, 0x6e, 0x58,
+}
+
+func TestDecompress(t *testing.T) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	w.Write([]byte(compressedCNF))
+	w.Close()
+
+	var xzBuffer bytes.Buffer
+	x, err := xz.NewWriter(&xzBuffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x.Write([]byte(compressedCNF))
+	x.Close()
+
+	inputs := map[string][]byte{
+		"plain": []byte(compressedCNF),
+		"gzip":  gz.Bytes(),
+		"bzip2": bzip2CNF,
+		"xz":    xzBuffer.Bytes(),
+	}
+	for name, input := range inputs {
+		sat, err := reader.Process(bytes.NewReader(input))
+		if err != nil {
+			t.Errorf("Could not read %v input: %v", name, err)
+			continue
+		}
+		if sat.AtomCount != 2 || len(sat.Clauses) != 2 || sat.Clauses[0][0] != -2 {
+			t.Errorf("Expected 2 atoms and 2 clauses from %v input, found %+v", name, sat)
+		}
+	}
+
+	// A truncated stream fails like a read error of plain input
+	_, err = reader.Process(bytes.NewReader(gz.Bytes()[:gz.Len()-8]))
+	if err == nil || !strings.Contains(err.Error(), "input could not be read") {
+		t.Errorf("Expected a read error for truncated gzip input, found %v", err)
+	}
+}
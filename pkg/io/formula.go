@@ -0,0 +1,302 @@
+package io
+
+/*
+The formula file reads and writes SATFiles in the formats the tool supports, used to convert between them.
+*/
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+FormulaFormat is an enum defining the formats a SATFile is read from and written to
+*/
+type FormulaFormat uint
+
+const (
+	DIMCAS_FORMAT FormulaFormat = iota // DIMCAS CNF with a `p cnf` header
+	JSON_FORMAT                        // The SATFile as a JSON object
+)
+
+// Options of writing a SATFile
+type WriteOptions struct {
+	Comments  []string // Written as `c` lines before the header, ignored by JSON
+	Normalize bool     // Sort the literals of every clause and remove repeated literals and clauses and tautologies
+}
+
+// Parses the name of a FormulaFormat as given on the command line
+func ParseFormulaFormat(name string) (FormulaFormat, error) {
+	switch name {
+	case "dimacs", "cnf":
+		return DIMCAS_FORMAT, nil
+	case "json":
+		return JSON_FORMAT, nil
+	}
+	return DIMCAS_FORMAT, handler.Throw("Unknown formula format: "+name, nil)
+}
+
+// Guesses the FormulaFormat of a file from its extension, ignoring the extension of a compression format
+func FormulaFormatOf(filename string) FormulaFormat {
+	if strings.HasSuffix(trimCompression(filename), ".json") {
+		return JSON_FORMAT
+	}
+	return DIMCAS_FORMAT
+}
+
+// Removes the extension of a compression format from a filename
+func trimCompression(filename string) string {
+	for _, ext := range []string{".gz", ".bz2", ".xz"} {
+		if strings.HasSuffix(filename, ext) {
+			return strings.TrimSuffix(filename, ext)
+		}
+	}
+	return filename
+}
+
+// Reads a SATFile in the given FormulaFormat
+func ReadFormula(r io.Reader, format FormulaFormat, mode ParseMode) (types.SATFile, error) {
+	switch format {
+	case DIMCAS_FORMAT:
+		return Parse(r, mode)
+	case JSON_FORMAT:
+		return ParseJSON(r, mode)
+	}
+	return types.SATFile{}, handler.Throw(fmt.Sprintf("Unknown formula format %v", format), nil)
+}
+
+// Writes a SATFile in the given FormulaFormat
+func WriteFormula(w io.Writer, format FormulaFormat, sat types.SATFile, options WriteOptions) error {
+	switch format {
+	case DIMCAS_FORMAT:
+		return WriteWithOptions(w, sat, options)
+	case JSON_FORMAT:
+		if options.Normalize {
+			sat = Normalize(sat)
+		}
+		return json.NewEncoder(w).Encode(sat)
+	}
+	return handler.Throw(fmt.Sprintf("Unknown formula format %v", format), nil)
+}
+
+/*
+ParseJSON reads a SATFile from a JSON object like {"atom_count": 2, "clause_count": 1, "clauses": [[1, -2]]}.
+Cardinality and XOR constraints are listed apart from the clauses, like "cardinalities": [{"literals": [1, 2], "bound": 1}]
+and "xors": [{"literals": [1, -2]}].
+The counts are checked like the header of DIMCAS input in the given ParseMode.
+*/
+func ParseJSON(r io.Reader, mode ParseMode) (sat types.SATFile, err error) {
+	if r, err = Decompress(r); err != nil {
+		return sat, err
+	}
+	if err = json.NewDecoder(r).Decode(&sat); err != nil {
+		return sat, handler.Throw("Invalid JSON formula: "+err.Error(), err)
+	}
+
+	var atoms uint
+	for i, d := range sat.Clauses {
+		for _, l := range d {
+			if l == 0 {
+				return sat, handler.Throw(fmt.Sprintf("Clause %v contains 0, which is not a literal", i+1), nil)
+			}
+			if uint(l.Atom()) > atoms {
+				atoms = uint(l.Atom())
+			}
+		}
+	}
+	for i, c := range sat.Cardinalities {
+		for _, l := range c.Literals {
+			if l == 0 {
+				return sat, handler.Throw(fmt.Sprintf("Cardinality constraint %v contains 0, which is not a literal", i+1), nil)
+			}
+			if uint(l.Atom()) > atoms {
+				atoms = uint(l.Atom())
+			}
+		}
+	}
+	for i, x := range sat.XORs {
+		for _, l := range x.Literals {
+			if l == 0 {
+				return sat, handler.Throw(fmt.Sprintf("XOR constraint %v contains 0, which is not a literal", i+1), nil)
+			}
+			if uint(l.Atom()) > atoms {
+				atoms = uint(l.Atom())
+			}
+		}
+	}
+
+	if mode == STRICT_PARSING {
+		if atoms > sat.AtomCount {
+			return sat, handler.Throw(fmt.Sprintf("Atom %v is not within the %v atoms of the formula", atoms, sat.AtomCount), nil)
+		}
+		if sat.ClauseCount != uint(len(sat.Clauses)) {
+			return sat, handler.Throw(fmt.Sprintf("The formula declares %v clauses, found %v", sat.ClauseCount, len(sat.Clauses)), nil)
+		}
+	}
+	if atoms > sat.AtomCount {
+		sat.AtomCount = atoms
+	}
+	sat.ClauseCount = uint(len(sat.Clauses))
+
+	return sat, nil
+}
+
+// Write writes a SATFile in DIMCAS format
+func Write(w io.Writer, sat types.SATFile) error {
+	return WriteWithOptions(w, sat, WriteOptions{})
+}
+
+/*
+WriteWithOptions writes a SATFile in DIMCAS format, with comments and normalized if asked to.
+Cardinality constraints are written as `k` lines and XOR constraints as `x` lines after the clauses,
+the header counts them.
+*/
+func WriteWithOptions(w io.Writer, sat types.SATFile, options WriteOptions) error {
+	if options.Normalize {
+		sat = Normalize(sat)
+	}
+
+	out := bufio.NewWriter(w)
+	for _, c := range options.Comments {
+		for _, line := range strings.Split(c, "\n") {
+			out.WriteString("c " + line + "\n")
+		}
+	}
+	fmt.Fprintf(out, "p cnf %v %v\n", sat.AtomCount, len(sat.Clauses)+len(sat.Cardinalities)+len(sat.XORs))
+
+	var line []byte
+	for _, d := range sat.Clauses {
+		line = line[:0]
+		for _, l := range d {
+			line = strconv.AppendInt(line, int64(l), 10)
+			line = append(line, ' ')
+		}
+		line = append(line, "0\n"...)
+		out.Write(line)
+	}
+	for _, c := range sat.Cardinalities {
+		line = append(line[:0], "k "...)
+		line = strconv.AppendUint(line, uint64(c.Bound), 10)
+		for _, l := range c.Literals {
+			line = append(line, ' ')
+			line = strconv.AppendInt(line, int64(l), 10)
+		}
+		line = append(line, " 0\n"...)
+		out.Write(line)
+	}
+	for _, x := range sat.XORs {
+		line = append(line[:0], 'x')
+		for i, l := range x.Literals {
+			if i > 0 {
+				line = append(line, ' ')
+			}
+			line = strconv.AppendInt(line, int64(l), 10)
+		}
+		if len(x.Literals) > 0 {
+			line = append(line, ' ')
+		}
+		line = append(line, "0\n"...)
+		out.Write(line)
+	}
+
+	return out.Flush()
+}
+
+/*
+Normalize returns a copy of the SATFile where the literals of every clause are sorted, repeated
+literals are removed and tautologies and repeated clauses are dropped. The first occurrence of a
+clause keeps its position. The literals of cardinality constraints are sorted and repeated literals
+removed, as a literal counts once towards the bound. XOR constraints are rewritten over their sorted
+atoms, where atoms occurring twice cancel out and only the first literal is negative if the parity
+asks for it. XOR constraints which always hold are dropped.
+*/
+func Normalize(sat types.SATFile) types.SATFile {
+	normalized := types.SATFile{AtomCount: sat.AtomCount}
+	seen := make(map[string]bool)
+
+	for _, d := range sat.Clauses {
+		clause := append(types.Disjunction{}, d...)
+		sort.Slice(clause, func(i, j int) bool { return clause[i] < clause[j] })
+
+		var (
+			kept      = clause[:0]
+			tautology bool
+		)
+		for i, l := range clause {
+			if i > 0 && l == clause[i-1] {
+				continue
+			}
+			// Negative literals come first when sorted, so the negation of a positive literal is before it
+			if l > 0 && containsSorted(clause[:i], l.Negate()) {
+				tautology = true
+				break
+			}
+			kept = append(kept, l)
+		}
+		if tautology {
+			continue
+		}
+
+		key := fmt.Sprint(kept)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized.Clauses = append(normalized.Clauses, kept)
+	}
+
+	for _, c := range sat.Cardinalities {
+		literals := append(types.Disjunction{}, c.Literals...)
+		sort.Slice(literals, func(i, j int) bool { return literals[i] < literals[j] })
+
+		kept := literals[:0]
+		for i, l := range literals {
+			if i == 0 || l != literals[i-1] {
+				kept = append(kept, l)
+			}
+		}
+		normalized.Cardinalities = append(normalized.Cardinalities, types.Cardinality{Literals: kept, Bound: c.Bound})
+	}
+
+	for _, x := range sat.XORs {
+		odd := make(map[types.Atom]bool)
+		parity := true
+		for _, l := range x.Literals {
+			odd[l.Atom()] = !odd[l.Atom()]
+			if l < 0 {
+				parity = !parity
+			}
+		}
+
+		literals := types.Disjunction{}
+		for a, ok := range odd {
+			if ok {
+				literals = append(literals, types.Literal(a))
+			}
+		}
+		if len(literals) == 0 && !parity {
+			continue
+		}
+		sort.Slice(literals, func(i, j int) bool { return literals[i] < literals[j] })
+		if !parity {
+			literals[0] = literals[0].Negate()
+		}
+		normalized.XORs = append(normalized.XORs, types.XOR{Literals: literals})
+	}
+
+	normalized.ClauseCount = uint(len(normalized.Clauses))
+	return normalized
+}
+
+func containsSorted(d types.Disjunction, lit types.Literal) bool {
+	i := sort.Search(len(d), func(i int) bool { return d[i] >= lit })
+	return i < len(d) && d[i] == lit
+}
@@ -0,0 +1,117 @@
+package io_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	reader "github.com/alanpjohn/go-cdcl/pkg/io"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestWrite(t *testing.T) {
+	sat := types.SATFile{
+		AtomCount: 3,
+		Clauses:   []types.Disjunction{{2, 1, 2}, {1, -1, 3}, {1, 2}, {-3}, {}},
+	}
+
+	var out bytes.Buffer
+	if err := reader.WriteWithOptions(&out, sat, reader.WriteOptions{Comments: []string{"a\nb"}}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "c a\nc b\np cnf 3 5\n2 1 2 0\n1 -1 3 0\n1 2 0\n-3 0\n0\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, found %q", expected, out.String())
+	}
+
+	out.Reset()
+	if err := reader.WriteWithOptions(&out, sat, reader.WriteOptions{Normalize: true}); err != nil {
+		t.Fatal(err)
+	}
+	expected = "p cnf 3 3\n1 2 0\n-3 0\n0\n"
+	if out.String() != expected {
+		t.Errorf("Expected the normalized formula %q, found %q", expected, out.String())
+	}
+
+	// Written formulas are read back unchanged, apart from the order of literals
+	read, err := reader.Parse(&out, reader.STRICT_PARSING)
+	if err != nil || read.AtomCount != 3 || len(read.Clauses) != 3 {
+		t.Errorf("Expected to read back the normalized formula, found %+v %v", read, err)
+	}
+}
+
+func TestWriteCardinality(t *testing.T) {
+	sat := types.SATFile{
+		AtomCount:     3,
+		Clauses:       []types.Disjunction{{1, 2}},
+		Cardinalities: []types.Cardinality{{Literals: types.Disjunction{3, -1, 3}, Bound: 2}},
+	}
+
+	var out bytes.Buffer
+	if err := reader.WriteWithOptions(&out, sat, reader.WriteOptions{Normalize: true}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "p cnf 3 2\n1 2 0\nk 2 -1 3 0\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, found %q", expected, out.String())
+	}
+
+	read, err := reader.Parse(&out, reader.STRICT_PARSING)
+	if err != nil || len(read.Clauses) != 1 || len(read.Cardinalities) != 1 || read.Cardinalities[0].Bound != 2 {
+		t.Errorf("Expected to read back the formula, found %+v %v", read, err)
+	}
+}
+
+func TestWriteXOR(t *testing.T) {
+	sat := types.SATFile{
+		AtomCount: 3,
+		XORs:      []types.XOR{{Literals: types.Disjunction{3, -1, 2, -2}}, {Literals: types.Disjunction{1, -1}}, {Literals: types.Disjunction{2, 2}}},
+	}
+
+	var out bytes.Buffer
+	if err := reader.Write(&out, sat); err != nil {
+		t.Fatal(err)
+	}
+	expected := "p cnf 3 3\nx3 -1 2 -2 0\nx1 -1 0\nx2 2 0\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, found %q", expected, out.String())
+	}
+
+	// 3 ⊕ ¬1 ⊕ 2 ⊕ ¬2 is 1 ⊕ 3 as ¬l is l ⊕ 1, the second constraint always holds and the third never does
+	out.Reset()
+	if err := reader.WriteWithOptions(&out, sat, reader.WriteOptions{Normalize: true}); err != nil {
+		t.Fatal(err)
+	}
+	expected = "p cnf 3 2\nx1 3 0\nx0\n"
+	if out.String() != expected {
+		t.Errorf("Expected the normalized formula %q, found %q", expected, out.String())
+	}
+}
+
+func TestFormulaJSON(t *testing.T) {
+	sat := types.SATFile{AtomCount: 2, ClauseCount: 2, Clauses: []types.Disjunction{{1, -2}, {2}}}
+
+	var out bytes.Buffer
+	if err := reader.WriteFormula(&out, reader.JSON_FORMAT, sat, reader.WriteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "{\"atom_count\":2,\"clause_count\":2,\"clauses\":[[1,-2],[2]]}\n" {
+		t.Errorf("Unexpected JSON %q", out.String())
+	}
+
+	read, err := reader.ReadFormula(&out, reader.JSON_FORMAT, reader.STRICT_PARSING)
+	if err != nil || read.AtomCount != 2 || len(read.Clauses) != 2 || read.Clauses[0][1] != -2 {
+		t.Errorf("Expected to read back %+v, found %+v %v", sat, read, err)
+	}
+
+	if _, err = reader.ParseJSON(strings.NewReader(`{"atom_count":1,"clause_count":1,"clauses":[[1,2]]}`), reader.STRICT_PARSING); err == nil {
+		t.Errorf("Expected an error for an atom beyond the atom count")
+	}
+	if _, err = reader.ParseJSON(strings.NewReader(`{"clauses":[[1,0]]}`), reader.LENIENT_PARSING); err == nil {
+		t.Errorf("Expected an error for a clause containing 0")
+	}
+
+	if reader.FormulaFormatOf("a.json.gz") != reader.JSON_FORMAT || reader.FormulaFormatOf("a.cnf") != reader.DIMCAS_FORMAT {
+		t.Errorf("Formats are not guessed from extensions")
+	}
+}
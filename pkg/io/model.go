@@ -0,0 +1,48 @@
+package io
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+ReadModel reads a model from the `v` lines of a solver output.
+Every other line, like the `s` and `c` lines, is skipped and reading stops at the terminating 0.
+*/
+func ReadModel(r io.Reader) ([]types.Literal, error) {
+	var model []types.Literal
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1<<16), 1<<30)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "v" {
+			continue
+		}
+
+		for _, f := range fields[1:] {
+			lit, err := strconv.ParseInt(f, 10, 64)
+			if err != nil {
+				return nil, handler.Throw(fmt.Sprintf("Invalid literal %q on line %v of model", f, line), err)
+			}
+			if lit == 0 {
+				return model, nil
+			}
+			model = append(model, types.Literal(lit))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, handler.Throw("Model could not be read", err)
+	}
+
+	return nil, handler.Throw("Model is not terminated by 0", nil)
+}
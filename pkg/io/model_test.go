@@ -0,0 +1,22 @@
+package io_test
+
+import (
+	"strings"
+	"testing"
+
+	reader "github.com/alanpjohn/go-cdcl/pkg/io"
+)
+
+func TestReadModel(t *testing.T) {
+	model, err := reader.ReadModel(strings.NewReader("c comment\ns SATISFIABLE\nv 1 -2\nv 3 0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(model) != 3 || model[0] != 1 || model[1] != -2 || model[2] != 3 {
+		t.Errorf("Expected [1 -2 3], found %v", model)
+	}
+
+	if _, err = reader.ReadModel(strings.NewReader("v 1 -2\n")); err == nil {
+		t.Errorf("Expected an error for a model without 0")
+	}
+}
@@ -0,0 +1,271 @@
+package io
+
+/*
+The opb file reads pseudo-Boolean problems in the OPB format of the pseudo-Boolean competitions.
+
+Comment lines start with `*`, and one of them before the first statement may hold the header `* #variable= <atoms> #constraint= <constraints>`.
+An optional objective `min: <terms> ;` is followed by constraints `<terms> <relation> <degree> ;`, where a term is an
+integer coefficient followed by a literal `x<atom>` or `~x<atom>` and the relation is `>=`, `=` or `<=`.
+*/
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+// Reports if a file holds a pseudo-Boolean problem from its extension, ignoring the extension of a compression format
+func IsOPB(filename string) bool {
+	return strings.HasSuffix(trimCompression(filename), ".opb")
+}
+
+/*
+ParseOPB reads an OPBFile. Constraints are turned into `>=` constraints, an equality into two of them.
+
+In STRICT_PARSING mode the header must match the atoms and constraints, and the last constraint must be
+terminated by `;`. Non-linear terms, where a coefficient is followed by several literals, are not supported.
+*/
+func ParseOPB(r io.Reader, mode ParseMode) (opb types.OPBFile, err error) {
+	var (
+		header      bool           // The header was read
+		atoms       uint           // Largest atom in the constraints
+		constraints uint           // No of constraints declared by the header
+		found       uint           // No of constraints read
+		statement   bool           // A constraint or the objective is being read
+		objective   bool           // The objective is being read
+		terms       []types.PBTerm // Terms of the current statement
+		coefficient int64          // Coefficient waiting for its literal
+		pending     bool           // A coefficient is waiting for its literal
+		relation    string         // Relation of the current constraint, empty till it is read
+		degree      int64          // Degree of the current constraint
+		complete    bool           // The degree of the current constraint was read
+	)
+
+	if r, err = Decompress(r); err != nil {
+		return opb, err
+	}
+
+	// Adds the current statement to the OPBFile
+	finish := func(t *tokenizer) error {
+		switch {
+		case pending:
+			return t.errorf("coefficient %v has no literal", coefficient)
+		case objective:
+			if relation != "" {
+				return t.errorf("the objective has a relation")
+			}
+			if opb.Objective != nil {
+				return t.errorf("repeated objective")
+			}
+			opb.Objective = append([]types.PBTerm{}, terms...)
+		case !complete:
+			return t.errorf("the constraint has no relation and degree")
+		default:
+			negated := make([]types.PBTerm, len(terms))
+			for i, term := range terms {
+				negated[i] = types.PBTerm{Coefficient: -term.Coefficient, Literal: term.Literal}
+			}
+			if relation != "<=" {
+				opb.Constraints = append(opb.Constraints, types.PBConstraint{Terms: terms, Degree: degree})
+			}
+			if relation != ">=" {
+				opb.Constraints = append(opb.Constraints, types.PBConstraint{Terms: negated, Degree: -degree})
+			}
+			found++
+		}
+
+		statement, objective, terms, pending, relation, complete = false, false, nil, false, "", false
+		return nil
+	}
+
+	t := constructTokenizer(r)
+	for {
+		ok, err := t.next()
+		if err != nil {
+			return opb, err
+		}
+		if !ok {
+			break
+		}
+
+		if t.first && t.token[0] == '*' {
+			if !header && !statement && found == 0 && opb.Objective == nil {
+				if header, opb.AtomCount, constraints, err = parseOPBHeader(t); err != nil {
+					return opb, err
+				}
+				if header {
+					logger.Info(fmt.Sprintf("Atom Count : %v, Constraint Count : %v", opb.AtomCount, constraints))
+				}
+				continue
+			}
+			if err = t.skipLine(); err != nil {
+				return opb, err
+			}
+			continue
+		}
+
+		end := t.token[len(t.token)-1] == ';'
+		if end {
+			t.token = t.token[:len(t.token)-1]
+		}
+
+		if len(t.token) > 0 {
+			token := string(t.token)
+			switch {
+			case token == "min:":
+				if statement {
+					return opb, t.errorf("the objective must start a statement")
+				}
+				objective = true
+			case token == ">=" || token == "=" || token == "<=":
+				if objective || relation != "" || pending {
+					return opb, t.errorf("unexpected relation %q", token)
+				}
+				relation = token
+			case relation != "":
+				if complete {
+					return opb, t.errorf("expected \";\" after the degree, found %q", token)
+				}
+				if degree, err = t.integer(); err != nil {
+					return opb, err
+				}
+				complete = true
+			case token[0] == 'x' || token[0] == '~':
+				lit, err := opbLiteral(t)
+				if err != nil {
+					return opb, err
+				}
+				if !pending {
+					return opb, t.errorf("literal %q has no coefficient, non-linear terms are not supported", token)
+				}
+				if uint(lit.Atom()) > atoms {
+					atoms = uint(lit.Atom())
+				}
+				terms = append(terms, types.PBTerm{Coefficient: coefficient, Literal: lit})
+				pending = false
+			default:
+				if pending {
+					return opb, t.errorf("coefficient %v has no literal", coefficient)
+				}
+				if coefficient, err = t.integer(); err != nil {
+					return opb, err
+				}
+				pending = true
+			}
+			statement = true
+		}
+
+		if end {
+			if err = finish(t); err != nil {
+				return opb, err
+			}
+		}
+	}
+
+	if statement {
+		if mode == STRICT_PARSING {
+			return opb, handler.Throw(fmt.Sprintf("Line %v: the last constraint is not terminated by ;", t.line), nil)
+		}
+		if err = finish(t); err != nil {
+			return opb, err
+		}
+	}
+
+	if header && mode == STRICT_PARSING {
+		if atoms > opb.AtomCount {
+			return opb, handler.Throw(fmt.Sprintf("The header declares %v atoms, found atom %v", opb.AtomCount, atoms), nil)
+		}
+		if found != constraints {
+			return opb, handler.Throw(fmt.Sprintf("The header declares %v constraints, found %v", constraints, found), nil)
+		}
+	}
+	if atoms > opb.AtomCount {
+		opb.AtomCount = atoms
+	}
+
+	logger.Info("Processed OPB file")
+	return opb, nil
+}
+
+// Reads the rest of a comment line, which is the header if it is `* #variable= <atoms> #constraint= <constraints>`
+func parseOPBHeader(t *tokenizer) (header bool, atoms uint, constraints uint, err error) {
+	var counts [2]int64
+	for i, key := range []string{"#variable=", "#constraint="} {
+		ok, err := t.next()
+		if err != nil {
+			return false, 0, 0, err
+		}
+		if !ok || t.first {
+			// The comment ends here, the token on the next line is read again
+			if ok {
+				t.back()
+			}
+			return false, 0, 0, nil
+		}
+		if string(t.token) != key {
+			return false, 0, 0, t.skipLine()
+		}
+
+		if ok, err = t.next(); err != nil {
+			return false, 0, 0, err
+		} else if !ok || t.first {
+			return false, 0, 0, t.errorf("expected the header \"* #variable= <atoms> #constraint= <constraints>\"")
+		}
+		if counts[i], err = t.integer(); err != nil {
+			return false, 0, 0, err
+		}
+		if counts[i] < 0 || i == 0 && counts[i] > math.MaxInt32 {
+			return false, 0, 0, t.errorf("count %v is out of range", counts[i])
+		}
+	}
+
+	return true, uint(counts[0]), uint(counts[1]), t.skipLine()
+}
+
+// Parses the last token as a literal x<atom> or ~x<atom>
+func opbLiteral(t *tokenizer) (types.Literal, error) {
+	token := t.token
+	negated := token[0] == '~'
+	if negated {
+		token = token[1:]
+	}
+	if len(token) < 2 || token[0] != 'x' {
+		return 0, t.errorf("expected a literal, found %q", t.token)
+	}
+
+	var atom int64
+	for _, d := range token[1:] {
+		if d < '0' || d > '9' {
+			return 0, t.errorf("expected a literal, found %q", t.token)
+		}
+		atom = 10*atom + int64(d-'0')
+		if atom > math.MaxInt32 {
+			return 0, t.errorf("literal %q is out of range", t.token)
+		}
+	}
+	if atom == 0 {
+		return 0, t.errorf("literal %q is out of range", t.token)
+	}
+
+	if negated {
+		return types.Literal(-atom), nil
+	}
+	return types.Literal(atom), nil
+}
+
+// Opens the file and reads an OPBFile from it
+func ReadOPBFile(filename string, mode ParseMode) (types.OPBFile, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return types.OPBFile{}, handler.Throw("File could not be read: "+filename, err)
+	}
+	defer file.Close()
+
+	return ParseOPB(file, mode)
+}
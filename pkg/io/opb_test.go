@@ -0,0 +1,83 @@
+package io_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	reader "github.com/alanpjohn/go-cdcl/pkg/io"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestParseOPB(t *testing.T) {
+	input := "* #variable= 4 #constraint= 3\n* comment\nmin: +1 x1 -2 ~x2 ;\n+1 x1 +2 x2\n>= 1 ;\n-1 x3 +1 ~x4 <= -1 ;\n+2 x1 +1 x4 = 2;\n"
+
+	opb, err := reader.ParseOPB(strings.NewReader(input), reader.STRICT_PARSING)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opb.AtomCount != 4 || len(opb.Objective) != 2 || opb.Objective[1] != (types.PBTerm{Coefficient: -2, Literal: -2}) {
+		t.Fatalf("Unexpected objective %+v", opb)
+	}
+
+	// The equality is read as two constraints
+	if len(opb.Constraints) != 4 {
+		t.Fatalf("Expected 4 constraints, found %+v", opb.Constraints)
+	}
+	if c := opb.Constraints[1]; c.Degree != 1 || c.Terms[0] != (types.PBTerm{Coefficient: 1, Literal: 3}) || c.Terms[1] != (types.PBTerm{Coefficient: -1, Literal: -4}) {
+		t.Errorf("Expected the <= constraint to be negated, found %+v", c)
+	}
+	if c := opb.Constraints[3]; c.Degree != -2 || c.Terms[0].Coefficient != -2 {
+		t.Errorf("Expected the second half of the equality, found %+v", c)
+	}
+
+	// A problem without objective or header
+	if opb, err = reader.ParseOPB(strings.NewReader("+3 x2 >= 2 ;\n"), reader.STRICT_PARSING); err != nil || opb.Objective != nil || opb.AtomCount != 2 {
+		t.Errorf("Expected a decision problem over 2 atoms, found %+v %v", opb, err)
+	}
+
+	if opb, err = reader.ParseOPB(strings.NewReader("min: ;\n+1 x1 >= 1"), reader.LENIENT_PARSING); err != nil || opb.Objective == nil || len(opb.Constraints) != 1 {
+		t.Errorf("Expected an empty objective and an unterminated constraint, found %+v %v", opb, err)
+	}
+}
+
+func TestParseOPBErrors(t *testing.T) {
+	inputs := map[string]string{
+		"non-linear":        "+1 x1 x2 >= 1 ;\n",
+		"no coefficient":    "x1 >= 1 ;\n",
+		"no relation":       "+1 x1 ;\n",
+		"bad literal":       "+1 y1 >= 1 ;\n",
+		"two relations":     "+1 x1 >= = 1 ;\n",
+		"atom out of range": "* #variable= 1 #constraint= 1\n+1 x2 >= 1 ;\n",
+		"count mismatch":    "* #variable= 1 #constraint= 2\n+1 x1 >= 1 ;\n",
+		"unterminated":      "+1 x1 >= 1\n",
+		"late objective":    "+1 x1 >= 1 ;\nmin: +1 x1 ; min: +1 x1 ;\n",
+	}
+	for name, input := range inputs {
+		if _, err := reader.ParseOPB(strings.NewReader(input), reader.STRICT_PARSING); err == nil {
+			t.Errorf("%v: expected an error for %q", name, input)
+		}
+	}
+
+	if !reader.IsOPB("a.opb.bz2") || reader.IsOPB("a.wcnf") {
+		t.Errorf("Expected OPB files to be recognised by their extension")
+	}
+}
+
+func TestWritePB(t *testing.T) {
+	cost := int64(-3)
+	result := reader.Result{
+		Solution: types.OPTIMUM,
+		Model:    []types.Literal{-1, 2},
+		Problem:  reader.PB_PROBLEM,
+		Cost:     &cost,
+	}
+
+	var out bytes.Buffer
+	if err := reader.WriteResult(&out, reader.COMPETITION_OUTPUT, result); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "o -3\ns OPTIMUM FOUND\nv -x1 x2\n" {
+		t.Errorf("Unexpected competition output %q", out.String())
+	}
+}
@@ -2,19 +2,28 @@
 package io
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"sort"
-	"strconv"
-	"strings"
 
 	handler "github.com/alanpjohn/go-cdcl/pkg/error"
 	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
 	types "github.com/alanpjohn/go-cdcl/pkg/types"
 )
 
+/*
+ParseMode is an enum defining how strictly DIMCAS input is checked against its header
+*/
+type ParseMode uint
+
+const (
+	LENIENT_PARSING ParseMode = iota // The header may be missing or disagree with the clauses
+	STRICT_PARSING                   // A single header must come first and match the clauses, which must all end in 0
+)
+
 // Utility function to check if file exists
 func fileExists(filename string) (bool, error) {
 	if _, err := os.Stat(filename); err == nil {
@@ -26,71 +35,216 @@ func fileExists(filename string) (bool, error) {
 	}
 }
 
-// Extracts SATFile from the input buffer
-func Process(f *os.File) (sat types.SATFile, err error) {
+// Extracts SATFile from the input stream, refer Parse
+func Process(r io.Reader) (types.SATFile, error) {
+	return Parse(r, LENIENT_PARSING)
+}
 
-	fileScanner := bufio.NewScanner(f)
+/*
+Parse reads a SATFile in DIMCAS format from the input stream.
 
-	fileScanner.Split(bufio.ScanLines)
+Compressed input is decompressed first, refer Decompress. Tokens may be separated by any whitespace,
+a clause may span several lines and a line may hold several clauses. Lines starting with `c` are
+comments and a `%` token ends the input, as in SATLIB files. Errors give the line and column of the
+offending token.
 
-	var atomCount int
-	var clauseCount int
-	var clauses []types.Disjunction
+A clause starting with `k <bound>` is a cardinality constraint, `k 2 1 -2 3 0` asks for at least 2 of
+the literals 1, -2 and 3 to be true. A clause starting with `x` is an XOR constraint as read by
+CryptoMiniSat, `x1 -2 3 0` or `x 1 -2 3 0` asks for an odd no of the literals to be true. The header
+counts cardinality and XOR constraints among the clauses.
+
+In LENIENT_PARSING mode the header may be missing or come after clauses, atoms beyond the header are
+added to the atom count, a different no of clauses is only logged and the last clause may miss its 0.
+*/
+func Parse(r io.Reader, mode ParseMode) (sat types.SATFile, err error) {
+	var (
+		header  bool              // The header was read
+		clause  types.Disjunction // Literals of the current clause
+		atoms   uint              // Largest atom in the clauses
+		clauses uint              // No of clauses declared by the header
+		bound   int64             // Bound of the current clause, -1 unless it is a cardinality constraint
+		xor     bool              // The current clause is an XOR constraint
+	)
+	bound = -1
+
+	if r, err = Decompress(r); err != nil {
+		return sat, err
+	}
 
-	for fileScanner.Scan() {
-		line := fileScanner.Text()
-		line = strings.TrimSpace(line)
-		items := strings.Split(line, " ")
+	t := constructTokenizer(r)
+	for {
+		ok, err := t.next()
+		if err != nil {
+			return sat, err
+		}
+		if !ok {
+			break
+		}
 
-		if items[0] == "c" {
-			logger.Info("Comment: " + line)
-		} else if items[0] == "p" {
-			logger.Info("Atom Count :" + items[2])
-			if atomCount, err = strconv.Atoi(items[2]); err != nil {
-				return
+		if t.first && t.token[0] == 'c' {
+			if err = t.skipLine(); err != nil {
+				return sat, err
 			}
+			continue
+		}
+		if len(t.token) == 1 && t.token[0] == '%' {
+			break
+		}
 
-			logger.Info("Clause Count :" + items[3])
-			if clauseCount, err = strconv.Atoi(items[3]); err != nil {
-				return
+		if t.first && len(t.token) == 1 && t.token[0] == 'p' {
+			if header {
+				return sat, t.errorf("repeated header")
 			}
-		} else {
-			var val int
-			var arr []int
-			for i := 0; i < len(items); i++ {
-				if val, err = strconv.Atoi(items[i]); err != nil {
-					return
-				}
-				if (val > atomCount && val > 0) || (val < 0 && val < -atomCount) {
-					return sat, handler.Throw("Invalid Literal found: "+items[i], nil)
-				}
-				if val == 0 {
-					break
-				}
-				arr = append(arr, val)
+			if mode == STRICT_PARSING && (len(sat.Clauses) > 0 || len(clause) > 0) {
+				return sat, t.errorf("header after clauses")
 			}
-			var d types.Disjunction
-			sort.Ints(arr)
-			for _, v := range arr {
-				d = append(d, types.Literal(v))
+			if sat.AtomCount, clauses, err = parseHeader(t); err != nil {
+				return sat, err
 			}
-			logger.Info(fmt.Sprintf("%v", d))
-			clauses = append(clauses, d)
+			header = true
+			logger.Info(fmt.Sprintf("Atom Count : %v, Clause Count : %v", sat.AtomCount, clauses))
+			continue
+		}
+
+		if mode == STRICT_PARSING && !header {
+			return sat, t.errorf("expected the header \"p cnf <atoms> <clauses>\", found %q", t.token)
+		}
+
+		if len(t.token) == 1 && t.token[0] == 'k' && len(clause) == 0 && bound < 0 {
+			if ok, err := t.next(); err != nil {
+				return sat, err
+			} else if !ok {
+				return sat, handler.Throw(fmt.Sprintf("Line %v: the cardinality constraint has no bound", t.line), nil)
+			}
+			if bound, err = t.integer(); err != nil {
+				return sat, err
+			}
+			if bound < 0 || bound > math.MaxInt32 {
+				return sat, t.errorf("bound %v is out of range", bound)
+			}
+			continue
+		}
+
+		if t.token[0] == 'x' && len(clause) == 0 && bound < 0 && !xor {
+			xor = true
+			if len(t.token) == 1 {
+				continue
+			}
+			// The first literal may follow the x without a space
+			t.token = t.token[1:]
+			t.column++
+		}
+
+		lit, err := t.integer()
+		if err != nil {
+			return sat, err
+		}
+		if lit == 0 {
+			sat = addClause(sat, clause, bound, xor)
+			clause, bound, xor = nil, -1, false
+			continue
+		}
+		if lit > math.MaxInt32 || lit < -math.MaxInt32 {
+			return sat, t.errorf("literal %v is out of range", lit)
+		}
+
+		l := types.Literal(lit)
+		if mode == STRICT_PARSING && uint(l.Atom()) > sat.AtomCount {
+			return sat, t.errorf("literal %v is not within the %v atoms of the header", lit, sat.AtomCount)
 		}
+		if uint(l.Atom()) > atoms {
+			atoms = uint(l.Atom())
+		}
+		clause = append(clause, l)
+	}
 
+	if len(clause) > 0 || bound >= 0 || xor {
+		if mode == STRICT_PARSING {
+			return sat, handler.Throw(fmt.Sprintf("Line %v: the last clause is not terminated by 0", t.line), nil)
+		}
+		sat = addClause(sat, clause, bound, xor)
+	}
+
+	found := uint(len(sat.Clauses) + len(sat.Cardinalities) + len(sat.XORs))
+	if !header {
+		if mode == STRICT_PARSING {
+			return sat, handler.Throw("The header \"p cnf <atoms> <clauses>\" is missing", nil)
+		}
+		clauses = found
+	}
+	if atoms > sat.AtomCount {
+		logger.Info(fmt.Sprintf("Atom count raised from %v to %v", sat.AtomCount, atoms))
+		sat.AtomCount = atoms
 	}
-	sat.AtomCount = uint(atomCount)
-	sat.ClauseCount = uint(clauseCount)
-	sat.Clauses = clauses
+	if clauses != found {
+		if mode == STRICT_PARSING {
+			return sat, handler.Throw(fmt.Sprintf("The header declares %v clauses, found %v", clauses, found), nil)
+		}
+		logger.Info(fmt.Sprintf("The header declares %v clauses, found %v", clauses, found))
+	}
+	sat.ClauseCount = uint(len(sat.Clauses))
+
 	logger.Info("Processed SAT file")
+	return sat, nil
+}
+
+// Adds the clause read to the SATFile, as a cardinality constraint if it has a bound or as an XOR constraint
+func addClause(sat types.SATFile, clause types.Disjunction, bound int64, xor bool) types.SATFile {
+	switch {
+	case xor:
+		sat.XORs = append(sat.XORs, types.XOR{Literals: sortClause(clause)})
+	case bound >= 0:
+		sat.Cardinalities = append(sat.Cardinalities, types.Cardinality{Literals: sortClause(clause), Bound: uint(bound)})
+	default:
+		sat.Clauses = append(sat.Clauses, sortClause(clause))
+	}
+	return sat
+}
 
-	defer f.Close()
+// Reads the rest of the header after `p`
+func parseHeader(t *tokenizer) (atoms uint, clauses uint, err error) {
+	var counts [2]int64
 
-	return sat, err
+	if ok, err := t.next(); err != nil {
+		return 0, 0, err
+	} else if !ok || t.first || string(t.token) != "cnf" {
+		return 0, 0, t.errorf("expected the header \"p cnf <atoms> <clauses>\"")
+	}
+	for i := range counts {
+		if ok, err := t.next(); err != nil {
+			return 0, 0, err
+		} else if !ok || t.first {
+			return 0, 0, t.errorf("expected the header \"p cnf <atoms> <clauses>\"")
+		}
+		if counts[i], err = t.integer(); err != nil {
+			return 0, 0, err
+		}
+		if counts[i] < 0 || counts[i] > math.MaxInt32 {
+			return 0, 0, t.errorf("count %v is out of range", counts[i])
+		}
+	}
+
+	return uint(counts[0]), uint(counts[1]), nil
 }
 
-// Open filename provided by user to process file contents into a SATFile
+// Sorts the literals of a clause in increasing order
+func sortClause(d types.Disjunction) types.Disjunction {
+	sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+	return d
+}
+
+// Open filename provided by user to process file contents into a SATFile, refer ReadFileMode
 func ReadFile(filename string) (out types.SATFile, err error) {
+	return ReadFileMode(filename, LENIENT_PARSING)
+}
+
+// Opens the file and reads a SATFile in DIMCAS format from it in the given ParseMode
+func ReadFileMode(filename string, mode ParseMode) (out types.SATFile, err error) {
+	return ReadFormulaFile(filename, DIMCAS_FORMAT, mode)
+}
+
+// Opens the file and reads a SATFile from it in the given FormulaFormat
+func ReadFormulaFile(filename string, format FormulaFormat, mode ParseMode) (out types.SATFile, err error) {
 	if filename == "" {
 		return types.SATFile{}, handler.Throw("Please input a file", nil)
 	}
@@ -100,7 +254,9 @@ func ReadFile(filename string) (out types.SATFile, err error) {
 	}
 	file, e := os.Open(filename)
 	if e != nil {
-		return types.SATFile{}, handler.Throw("File could not be read", err)
+		return types.SATFile{}, handler.Throw("File could not be read", e)
 	}
-	return Process(file)
+	defer file.Close()
+
+	return ReadFormula(file, format, mode)
 }
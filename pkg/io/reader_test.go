@@ -0,0 +1,125 @@
+package io_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	reader "github.com/alanpjohn/go-cdcl/pkg/io"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestParse(t *testing.T) {
+	input := "c comment\np cnf 4  3\n2\t-1 0 3\n  4 0\n\r\n-4 0\n%\n0\n"
+
+	for _, mode := range []reader.ParseMode{reader.LENIENT_PARSING, reader.STRICT_PARSING} {
+		sat, err := reader.Parse(strings.NewReader(input), mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []types.Disjunction{{-1, 2}, {3, 4}, {-4}}
+		if sat.AtomCount != 4 || sat.ClauseCount != 3 || len(sat.Clauses) != len(expected) {
+			t.Fatalf("Expected 4 atoms and clauses %v, found %+v", expected, sat)
+		}
+		for i, d := range expected {
+			for j, l := range d {
+				if sat.Clauses[i][j] != l {
+					t.Errorf("Expected clause %v, found %v", d, sat.Clauses[i])
+				}
+			}
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		input   string
+		mode    reader.ParseMode
+		message string
+	}{
+		{"p cnf 2 1\n1  x 0\n", reader.LENIENT_PARSING, "Line 2, column 4: expected an integer"},
+		{"p cnf 2 1\n1 3 0\n", reader.STRICT_PARSING, "Line 2, column 3: literal 3 is not within"},
+		{"p cnf 2 2\n1 2 0\n", reader.STRICT_PARSING, "The header declares 2 clauses, found 1"},
+		{"p cnf 2 1\n1 2\n", reader.STRICT_PARSING, "not terminated by 0"},
+		{"1 2 0\n", reader.STRICT_PARSING, "Line 1, column 1: expected the header"},
+		{"p cnf 2 1\np cnf 2 1\n", reader.LENIENT_PARSING, "Line 2, column 1: repeated header"},
+		{"p dnf 2 1\n", reader.LENIENT_PARSING, "Line 1, column 3: expected the header"},
+		{"p cnf 2 1\nk -1 1 2 0\n", reader.STRICT_PARSING, "Line 2, column 3: bound -1 is out of range"},
+		{"p cnf 2 1\n1 k 2 0\n", reader.STRICT_PARSING, "Line 2, column 3: expected an integer"},
+	}
+
+	for _, c := range cases {
+		_, err := reader.Parse(strings.NewReader(c.input), c.mode)
+		if err == nil || !strings.Contains(err.Error(), c.message) {
+			t.Errorf("Expected error %q for %q, found %v", c.message, c.input, err)
+		}
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	// No header, an atom beyond the header count and a last clause without 0 are accepted
+	sat, err := reader.Parse(strings.NewReader("1 -5 0\n2"), reader.LENIENT_PARSING)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sat.AtomCount != 5 || sat.ClauseCount != 2 || len(sat.Clauses[1]) != 1 {
+		t.Errorf("Expected 5 atoms and 2 clauses, found %+v", sat)
+	}
+}
+
+func TestParseCardinality(t *testing.T) {
+	sat, err := reader.Parse(strings.NewReader("p cnf 3 3\nk 2 3 -1 2 0\n1 2 0 k 0\n0\n"), reader.STRICT_PARSING)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sat.ClauseCount != 1 || len(sat.Cardinalities) != 2 {
+		t.Fatalf("Expected 1 clause and 2 cardinality constraints, found %+v", sat)
+	}
+	if c := sat.Cardinalities[0]; c.Bound != 2 || len(c.Literals) != 3 || c.Literals[0] != -1 || c.Literals[2] != 3 {
+		t.Errorf("Expected at least 2 of [-1 2 3], found %+v", c)
+	}
+	if c := sat.Cardinalities[1]; c.Bound != 0 || len(c.Literals) != 0 {
+		t.Errorf("Expected an empty constraint with bound 0, found %+v", c)
+	}
+}
+
+func TestParseXOR(t *testing.T) {
+	sat, err := reader.Parse(strings.NewReader("p cnf 3 4\nx1 -2 0\nx 3 2 1 0\n1 0\nx0\n"), reader.STRICT_PARSING)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sat.ClauseCount != 1 || len(sat.XORs) != 3 {
+		t.Fatalf("Expected 1 clause and 3 XOR constraints, found %+v", sat)
+	}
+	if x := sat.XORs[0].Literals; len(x) != 2 || x[0] != -2 || x[1] != 1 {
+		t.Errorf("Expected the XOR of [-2 1], found %v", x)
+	}
+	if x := sat.XORs[1].Literals; len(x) != 3 || x[2] != 3 {
+		t.Errorf("Expected the XOR of [1 2 3], found %v", x)
+	}
+	if x := sat.XORs[2].Literals; len(x) != 0 {
+		t.Errorf("Expected an empty XOR constraint, found %v", x)
+	}
+
+	if _, err = reader.Parse(strings.NewReader("p cnf 2 1\nxy 0\n"), reader.STRICT_PARSING); err == nil || !strings.Contains(err.Error(), "Line 2, column 2: expected an integer") {
+		t.Errorf("Expected an error for a malformed x line, found %v", err)
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	// The header declares fewer clauses than the file holds, which only lenient parsing accepts
+	filename := filepath.Join(t.TempDir(), "sample.cnf")
+	if err := os.WriteFile(filename, []byte("p cnf 2 1\n1 -2 0\n2 0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sat, err := reader.ReadFile(filename)
+	if err != nil || len(sat.Clauses) != 2 {
+		t.Errorf("Expected 2 clauses, found %+v %v", sat, err)
+	}
+	if _, err = reader.ReadFileMode(filename, reader.STRICT_PARSING); err == nil {
+		t.Errorf("Expected strict parsing to reject the header")
+	}
+}
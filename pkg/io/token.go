@@ -0,0 +1,153 @@
+package io
+
+/*
+The token file splits DIMCAS style input into whitespace separated tokens while keeping track of their
+line and column, so that errors point to the offending token.
+*/
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+)
+
+// tokenizer reads tokens from a stream without holding more than a single token in memory
+type tokenizer struct {
+	in      *bufio.Reader
+	token   []byte // The last token, reused by the next call of next
+	first   bool   // The last token is the first of its line
+	line    int    // Line of the last token, from 1
+	column  int    // Column of the last token, from 1
+	newline bool   // No token was read on the current line yet
+	repeat  bool   // The next call of next returns the last token again
+
+	nextLine   int // Line of the next byte
+	nextColumn int // Column of the next byte
+}
+
+func constructTokenizer(r io.Reader) *tokenizer {
+	return &tokenizer{
+		in:         bufio.NewReaderSize(r, 1<<16),
+		newline:    true,
+		nextLine:   1,
+		nextColumn: 1,
+	}
+}
+
+// Reads the next byte, keeping track of the position of the byte after it
+func (t *tokenizer) readByte() (byte, error) {
+	b, err := t.in.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if b == '\n' {
+		t.nextLine++
+		t.nextColumn = 1
+		t.newline = true
+	} else {
+		t.nextColumn++
+	}
+	return b, nil
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\v' || b == '\f'
+}
+
+// Reads the next token, which is only valid till the following call. Returns false at the end of the input.
+func (t *tokenizer) next() (bool, error) {
+	if t.repeat {
+		t.repeat = false
+		return true, nil
+	}
+	t.token = t.token[:0]
+
+	var (
+		b   byte
+		err error
+	)
+	for {
+		line, column := t.nextLine, t.nextColumn
+		if b, err = t.readByte(); err != nil || !isSpace(b) {
+			t.line, t.column = line, column
+			break
+		}
+	}
+	if err == io.EOF {
+		return false, nil
+	} else if err != nil {
+		return false, t.readError(err)
+	}
+
+	t.first = t.newline
+	t.newline = false
+	for err == nil && !isSpace(b) {
+		t.token = append(t.token, b)
+		b, err = t.readByte()
+	}
+	if err != nil && err != io.EOF {
+		return false, t.readError(err)
+	}
+	return true, nil
+}
+
+// Makes the next call of next return the last token again
+func (t *tokenizer) back() {
+	t.repeat = true
+}
+
+// Skips the rest of the current line
+func (t *tokenizer) skipLine() error {
+	if t.newline {
+		return nil
+	}
+	for {
+		b, err := t.readByte()
+		if err == io.EOF || err == nil && b == '\n' {
+			return nil
+		} else if err != nil {
+			return t.readError(err)
+		}
+	}
+}
+
+// Builds an error for a failed read, like corrupt compressed input, pointing at the next byte
+func (t *tokenizer) readError(err error) error {
+	return handler.Throw(fmt.Sprintf("Line %v, column %v: input could not be read: %v", t.nextLine, t.nextColumn, err), err)
+}
+
+// Builds an error pointing at the last token
+func (t *tokenizer) errorf(format string, args ...interface{}) error {
+	return handler.Throw(fmt.Sprintf("Line %v, column %v: ", t.line, t.column)+fmt.Sprintf(format, args...), nil)
+}
+
+// Parses the last token as a decimal integer
+func (t *tokenizer) integer() (int64, error) {
+	digits := t.token
+	negative := len(digits) > 0 && digits[0] == '-'
+	if negative || len(digits) > 0 && digits[0] == '+' {
+		digits = digits[1:]
+	}
+	if len(digits) == 0 {
+		return 0, t.errorf("expected an integer, found %q", t.token)
+	}
+
+	var n int64
+	for _, d := range digits {
+		if d < '0' || d > '9' {
+			return 0, t.errorf("expected an integer, found %q", t.token)
+		}
+		if n > (math.MaxInt64-int64(d-'0'))/10 {
+			return 0, t.errorf("integer %s is too large", t.token)
+		}
+		n = 10*n + int64(d-'0')
+	}
+
+	if negative {
+		return -n, nil
+	}
+	return n, nil
+}
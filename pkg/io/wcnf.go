@@ -0,0 +1,211 @@
+package io
+
+/*
+The wcnf file reads weighted partial MaxSAT problems in both formats of the MaxSAT Evaluations.
+
+In the format used till 2021 a `p wcnf <atoms> <clauses> <top>` header comes first and every clause
+starts with its weight, clauses weighing at least top are hard. In the format used since 2022 there is
+no header, hard clauses start with `h` and soft clauses with their weight.
+*/
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+// Reports if a file holds a WCNF formula from its extension, ignoring the extension of a compression format
+func IsWCNF(filename string) bool {
+	return strings.HasSuffix(trimCompression(filename), ".wcnf")
+}
+
+/*
+ParseWCNF reads a WCNFFile in either WCNF format. Lexing, comments and errors follow Parse.
+
+In STRICT_PARSING mode a header must match the clauses and `h` clauses are only accepted without a
+header. Atoms beyond the header are added to the atom count in LENIENT_PARSING mode. An error is
+returned if the total weight of the soft clauses does not fit an int64.
+*/
+func ParseWCNF(r io.Reader, mode ParseMode) (wcnf types.WCNFFile, err error) {
+	var (
+		header  bool              // The header was read
+		top     uint64            // Weight from which clauses are hard, 0 if every clause is soft
+		clauses uint              // No of clauses declared by the header
+		start   = true            // The next token starts a clause
+		hard    bool              // The current clause is hard
+		weight  uint64            // Weight of the current clause
+		clause  types.Disjunction // Literals of the current clause
+		atoms   uint              // Largest atom in the clauses
+		total   uint64            // Total weight of the soft clauses, at most math.MaxInt64
+	)
+
+	if r, err = Decompress(r); err != nil {
+		return wcnf, err
+	}
+
+	t := constructTokenizer(r)
+	for {
+		ok, err := t.next()
+		if err != nil {
+			return wcnf, err
+		}
+		if !ok {
+			break
+		}
+
+		if t.first && t.token[0] == 'c' {
+			if err = t.skipLine(); err != nil {
+				return wcnf, err
+			}
+			continue
+		}
+
+		if t.first && start && len(t.token) == 1 && t.token[0] == 'p' {
+			if header {
+				return wcnf, t.errorf("repeated header")
+			}
+			if mode == STRICT_PARSING && len(wcnf.Hard)+len(wcnf.Soft) > 0 {
+				return wcnf, t.errorf("header after clauses")
+			}
+			if wcnf.AtomCount, clauses, top, err = parseWCNFHeader(t); err != nil {
+				return wcnf, err
+			}
+			header = true
+			logger.Info(fmt.Sprintf("Atom Count : %v, Clause Count : %v, Top : %v", wcnf.AtomCount, clauses, top))
+			continue
+		}
+
+		if start {
+			start = false
+			if len(t.token) == 1 && t.token[0] == 'h' {
+				if mode == STRICT_PARSING && header {
+					return wcnf, t.errorf("hard clauses start with their weight after a \"p wcnf\" header")
+				}
+				hard = true
+				continue
+			}
+
+			w, err := t.integer()
+			if err != nil {
+				return wcnf, err
+			}
+			if w <= 0 {
+				return wcnf, t.errorf("weight %v is not positive", w)
+			}
+			weight = uint64(w)
+			hard = top > 0 && weight >= top
+			if !hard {
+				// Costs are printed and compared as int64, so the total weight of the soft clauses must fit
+				if weight > math.MaxInt64-total {
+					return wcnf, t.errorf("the weights of the soft clauses overflow")
+				}
+				total += weight
+			}
+			continue
+		}
+
+		lit, err := t.integer()
+		if err != nil {
+			return wcnf, err
+		}
+		if lit == 0 {
+			addWCNFClause(&wcnf, sortClause(clause), hard, weight)
+			clause, start = nil, true
+			continue
+		}
+		if lit > math.MaxInt32 || lit < -math.MaxInt32 {
+			return wcnf, t.errorf("literal %v is out of range", lit)
+		}
+
+		l := types.Literal(lit)
+		if mode == STRICT_PARSING && header && uint(l.Atom()) > wcnf.AtomCount {
+			return wcnf, t.errorf("literal %v is not within the %v atoms of the header", lit, wcnf.AtomCount)
+		}
+		if uint(l.Atom()) > atoms {
+			atoms = uint(l.Atom())
+		}
+		clause = append(clause, l)
+	}
+
+	if !start {
+		if mode == STRICT_PARSING {
+			return wcnf, handler.Throw(fmt.Sprintf("Line %v: the last clause is not terminated by 0", t.line), nil)
+		}
+		addWCNFClause(&wcnf, sortClause(clause), hard, weight)
+	}
+
+	if atoms > wcnf.AtomCount {
+		wcnf.AtomCount = atoms
+	}
+	if found := uint(len(wcnf.Hard) + len(wcnf.Soft)); header && clauses != found {
+		if mode == STRICT_PARSING {
+			return wcnf, handler.Throw(fmt.Sprintf("The header declares %v clauses, found %v", clauses, found), nil)
+		}
+		logger.Info(fmt.Sprintf("The header declares %v clauses, found %v", clauses, found))
+	}
+
+	logger.Info("Processed WCNF file")
+	return wcnf, nil
+}
+
+// Reads the rest of the header after `p`, top is optional and 0 if it is missing
+func parseWCNFHeader(t *tokenizer) (atoms uint, clauses uint, top uint64, err error) {
+	if ok, err := t.next(); err != nil {
+		return 0, 0, 0, err
+	} else if !ok || t.first || string(t.token) != "wcnf" {
+		return 0, 0, 0, t.errorf("expected the header \"p wcnf <atoms> <clauses> <top>\"")
+	}
+
+	var counts [3]int64
+	for i := range counts {
+		ok, err := t.next()
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		if i == 2 && (!ok || t.first) {
+			// The header has no top, the token on the next line starts the first clause
+			if ok {
+				t.back()
+			}
+			return uint(counts[0]), uint(counts[1]), 0, nil
+		}
+		if !ok || t.first {
+			return 0, 0, 0, t.errorf("expected the header \"p wcnf <atoms> <clauses> <top>\"")
+		}
+		if counts[i], err = t.integer(); err != nil {
+			return 0, 0, 0, err
+		}
+		if counts[i] < 0 || i < 2 && counts[i] > math.MaxInt32 {
+			return 0, 0, 0, t.errorf("count %v is out of range", counts[i])
+		}
+	}
+
+	return uint(counts[0]), uint(counts[1]), uint64(counts[2]), nil
+}
+
+// Adds a clause to the hard or the soft clauses
+func addWCNFClause(wcnf *types.WCNFFile, clause types.Disjunction, hard bool, weight uint64) {
+	if hard {
+		wcnf.Hard = append(wcnf.Hard, clause)
+	} else {
+		wcnf.Soft = append(wcnf.Soft, clause)
+		wcnf.Weights = append(wcnf.Weights, weight)
+	}
+}
+
+// Opens the file and reads a WCNFFile from it
+func ReadWCNFFile(filename string, mode ParseMode) (types.WCNFFile, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return types.WCNFFile{}, handler.Throw("File could not be read: "+filename, err)
+	}
+	defer file.Close()
+
+	return ParseWCNF(file, mode)
+}
@@ -0,0 +1,104 @@
+package io_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	reader "github.com/alanpjohn/go-cdcl/pkg/io"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestParseWCNF(t *testing.T) {
+	inputs := map[string]string{
+		"old": "c old format\np wcnf 3 4 10\n10 1 2 0\n10 -1 0\n3 -2 3 0\n1 -3 0\n",
+		"new": "c new format\nh 1 2 0\nh -1 0\n3 -2 3 0\n1 -3 0\n",
+	}
+	for name, input := range inputs {
+		wcnf, err := reader.ParseWCNF(strings.NewReader(input), reader.STRICT_PARSING)
+		if err != nil {
+			t.Fatalf("%v: %v", name, err)
+		}
+		if wcnf.AtomCount != 3 || len(wcnf.Hard) != 2 || len(wcnf.Soft) != 2 {
+			t.Fatalf("%v: unexpected WCNF %+v", name, wcnf)
+		}
+		if wcnf.Hard[1][0] != -1 || wcnf.Soft[0][0] != -2 || wcnf.Weights[0] != 3 || wcnf.Weights[1] != 1 {
+			t.Errorf("%v: unexpected clauses %+v", name, wcnf)
+		}
+	}
+
+	// Without top every clause of the old format is soft
+	wcnf, err := reader.ParseWCNF(strings.NewReader("p wcnf 2 2\n4 1 0\n2 -2 0\n"), reader.STRICT_PARSING)
+	if err != nil || len(wcnf.Hard) != 0 || len(wcnf.Soft) != 2 || wcnf.Weights[0] != 4 {
+		t.Errorf("Expected 2 soft clauses, found %+v %v", wcnf, err)
+	}
+
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	w.Write([]byte(inputs["new"]))
+	w.Close()
+	if wcnf, err = reader.ParseWCNF(&compressed, reader.STRICT_PARSING); err != nil || len(wcnf.Soft) != 2 {
+		t.Errorf("Expected to read a compressed WCNF, found %+v %v", wcnf, err)
+	}
+}
+
+func TestParseWCNFErrors(t *testing.T) {
+	inputs := map[string]string{
+		"zero weight":     "h 1 0\n0 2 0\n",
+		"negative weight": "h 1 0\n-3 2 0\n",
+		"header mismatch": "p wcnf 2 3 10\n10 1 0\n1 2 0\n",
+		"h with header":   "p wcnf 2 2 10\nh 1 0\n1 2 0\n",
+		"bad weight":      "h 1 0\nx 2 0\n",
+		"weight overflow": "h 1 2 0\n9223372036854775807 -1 0\n9223372036854775807 -1 0\n3 -2 0\n",
+	}
+	for name, input := range inputs {
+		if _, err := reader.ParseWCNF(strings.NewReader(input), reader.STRICT_PARSING); err == nil {
+			t.Errorf("%v: expected an error for %q", name, input)
+		}
+	}
+
+	if !reader.IsWCNF("a.wcnf.xz") || reader.IsWCNF("a.cnf") {
+		t.Errorf("Expected WCNF files to be recognised by their extension")
+	}
+}
+
+func TestWriteMaxSAT(t *testing.T) {
+	cost := int64(4)
+	result := reader.Result{
+		Solution: types.OPTIMUM,
+		Model:    []types.Literal{-1, 2, 3},
+		Problem:  reader.MAXSAT_PROBLEM,
+		Cost:     &cost,
+	}
+
+	var out bytes.Buffer
+	if err := reader.WriteResult(&out, reader.COMPETITION_OUTPUT, result); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "o 4\ns OPTIMUM FOUND\nv 011\n" {
+		t.Errorf("Unexpected competition output %q", out.String())
+	}
+
+	// The `o` lines of streamed costs were printed during the search
+	out.Reset()
+	result.Streamed = true
+	if err := reader.WriteResult(&out, reader.COMPETITION_OUTPUT, result); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "s OPTIMUM FOUND\nv 011\n" {
+		t.Errorf("Unexpected competition output %q", out.String())
+	}
+
+	out.Reset()
+	if err := reader.WriteResult(&out, reader.JSON_OUTPUT, result); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"cost":4`) {
+		t.Errorf("Expected the cost in the JSON output, found %q", out.String())
+	}
+
+	if reader.ExitCode(types.OPTIMUM) != reader.EXIT_OPTIMUM {
+		t.Errorf("Expected exit code %v for OPTIMUM", reader.EXIT_OPTIMUM)
+	}
+}
@@ -0,0 +1,211 @@
+package io
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+OutputFormat is an enum defining how the result of the solver is printed
+*/
+type OutputFormat uint
+
+const (
+	COMPETITION_OUTPUT OutputFormat = iota // SAT competition `s`, `v` and `c` lines
+	PLAIN_OUTPUT                           // The solution followed by the model on a single line
+	JSON_OUTPUT                            // A single JSON object
+)
+
+// Width at which `v` lines are wrapped in the competition output
+const LINE_WIDTH = 80
+
+/*
+ProblemType is an enum defining the kind of problem a Result is about, which decides the conventions it is printed with
+*/
+type ProblemType uint
+
+const (
+	SAT_PROBLEM    ProblemType = iota // SAT competition, the model is a list of literals ending with 0
+	MAXSAT_PROBLEM                    // MaxSAT Evaluation, the model is a string of 0s and 1s
+	PB_PROBLEM                        // Pseudo-Boolean competition, the model is a list of literals x<atom> or -x<atom>
+)
+
+// Exit codes of the SAT competition, the MaxSAT Evaluation and the pseudo-Boolean competition
+const (
+	EXIT_SATISFIABLE   = 10
+	EXIT_UNSATISFIABLE = 20
+	EXIT_OPTIMUM       = 30
+	EXIT_UNKNOWN       = 0
+)
+
+// Result holds everything printed about a run of the solver
+type Result struct {
+	Solution types.Solution
+	Model    []types.Literal // Model[i] is the literal of atom i+1, only printed if satisfiable
+	Comments []string        // Printed as `c` lines in the competition output
+	Stats    *types.Stats    // Statistics of the search, printed as `c` lines after the comments, nil if there are none
+	Problem  ProblemType     // Kind of problem solved
+	Cost     *int64          // Cost of the model of an optimization problem, printed on an `o` line, nil if there is none
+	Streamed bool            // The cost of every better model was already printed as an `o` line during the search
+}
+
+// Returns true if the Result has a model to print
+func (result Result) hasModel() bool {
+	return result.Solution == types.SATISFIABLE || result.Solution == types.OPTIMUM
+}
+
+// Parses the name of an OutputFormat as given on the command line
+func ParseOutputFormat(name string) (OutputFormat, error) {
+	switch name {
+	case "competition":
+		return COMPETITION_OUTPUT, nil
+	case "plain":
+		return PLAIN_OUTPUT, nil
+	case "json":
+		return JSON_OUTPUT, nil
+	}
+	return COMPETITION_OUTPUT, handler.Throw("Unknown output format: "+name, nil)
+}
+
+// Returns the SAT competition exit code of a Solution
+func ExitCode(solution types.Solution) int {
+	switch solution {
+	case types.SATISFIABLE:
+		return EXIT_SATISFIABLE
+	case types.UNSATISFIABLE:
+		return EXIT_UNSATISFIABLE
+	case types.OPTIMUM:
+		return EXIT_OPTIMUM
+	}
+	return EXIT_UNKNOWN
+}
+
+// Writes the Result to w in the given OutputFormat
+func WriteResult(w io.Writer, format OutputFormat, result Result) (err error) {
+	switch format {
+	case COMPETITION_OUTPUT:
+		err = writeCompetition(w, result)
+	case PLAIN_OUTPUT:
+		err = writePlain(w, result)
+	case JSON_OUTPUT:
+		err = writeJSON(w, result)
+	default:
+		err = handler.Throw(fmt.Sprintf("Unknown output format %v", format), nil)
+	}
+	return err
+}
+
+/*
+Writes the comments and the statistics as `c` lines, the cost of the model as an `o` line unless it was
+streamed, the solution as a `s` line and the model as `v` lines.
+
+The model of a SAT problem is terminated by 0 and the model of a pseudo-Boolean problem gives every atom as
+x<atom> or -x<atom>, both are wrapped so that no line is longer than LINE_WIDTH. The model of a MaxSAT problem
+is a single `v` line of 0s and 1s, the value of atom i+1 being the character at i.
+*/
+func writeCompetition(w io.Writer, result Result) error {
+	var out strings.Builder
+
+	comments := result.Comments
+	if result.Stats != nil {
+		comments = append(append([]string{}, comments...), result.Stats.Summary()...)
+	}
+	for _, c := range comments {
+		out.WriteString("c " + c + "\n")
+	}
+	if result.Cost != nil && result.hasModel() && !result.Streamed {
+		out.WriteString(fmt.Sprintf("o %v\n", *result.Cost))
+	}
+	out.WriteString("s " + result.Solution.String() + "\n")
+
+	if result.hasModel() {
+		switch result.Problem {
+		case MAXSAT_PROBLEM:
+			values := make([]byte, len(result.Model))
+			for i, l := range result.Model {
+				values[i] = '0'
+				if l > 0 {
+					values[i] = '1'
+				}
+			}
+			out.WriteString("v " + string(values) + "\n")
+		case PB_PROBLEM:
+			lits := make([]string, len(result.Model))
+			for i, l := range result.Model {
+				lits[i] = fmt.Sprintf("x%v", l.Atom())
+				if l < 0 {
+					lits[i] = "-" + lits[i]
+				}
+			}
+			writeWrapped(&out, lits)
+		default:
+			lits := make([]string, len(result.Model)+1)
+			for i, l := range append(result.Model, 0) {
+				lits[i] = fmt.Sprint(l)
+			}
+			writeWrapped(&out, lits)
+		}
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// Writes the words on `v` lines no longer than LINE_WIDTH
+func writeWrapped(out *strings.Builder, words []string) {
+	line := "v"
+	for _, word := range words {
+		if len(line)+len(word)+1 > LINE_WIDTH {
+			out.WriteString(line + "\n")
+			line = "v"
+		}
+		line += " " + word
+	}
+	out.WriteString(line + "\n")
+}
+
+// Writes the solution, the cost of the model and the model on a line each
+func writePlain(w io.Writer, result Result) error {
+	var out strings.Builder
+
+	out.WriteString(result.Solution.String() + "\n")
+	if result.Cost != nil && result.hasModel() {
+		out.WriteString(fmt.Sprint(*result.Cost) + "\n")
+	}
+	if result.hasModel() {
+		lits := make([]string, len(result.Model))
+		for i, l := range result.Model {
+			lits[i] = fmt.Sprint(l)
+		}
+		out.WriteString(strings.Join(lits, " ") + "\n")
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// Writes the Result as a JSON object
+func writeJSON(w io.Writer, result Result) error {
+	object := struct {
+		Result   string          `json:"result"`
+		Cost     *int64          `json:"cost,omitempty"`
+		Model    []types.Literal `json:"model,omitempty"`
+		Comments []string        `json:"comments,omitempty"`
+		Stats    *types.Stats    `json:"stats,omitempty"`
+	}{
+		Result:   result.Solution.String(),
+		Comments: result.Comments,
+		Stats:    result.Stats,
+	}
+	if result.hasModel() {
+		object.Model = result.Model
+		object.Cost = result.Cost
+	}
+
+	return json.NewEncoder(w).Encode(object)
+}
@@ -0,0 +1,81 @@
+package io_test
+
+import (
+	"strings"
+	"testing"
+
+	reader "github.com/alanpjohn/go-cdcl/pkg/io"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestWriteCompetition(t *testing.T) {
+	var model []types.Literal
+	for a := 1; a <= 40; a++ {
+		model = append(model, types.Literal(-a))
+	}
+
+	var out strings.Builder
+	err := reader.WriteResult(&out, reader.COMPETITION_OUTPUT, reader.Result{
+		Solution: types.SATISFIABLE,
+		Model:    model,
+		Comments: []string{"atoms 40"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if lines[0] != "c atoms 40" || lines[1] != "s SATISFIABLE" {
+		t.Errorf("Expected comment and solution lines, found %q", lines[:2])
+	}
+
+	var values []string
+	for _, line := range lines[2:] {
+		if len(line) > reader.LINE_WIDTH || !strings.HasPrefix(line, "v ") {
+			t.Errorf("Malformed model line %q", line)
+		}
+		values = append(values, strings.Fields(line)[1:]...)
+	}
+	if len(values) != 41 || values[0] != "-1" || values[40] != "0" {
+		t.Errorf("Expected 40 literals terminated by 0, found %v", values)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var out strings.Builder
+	if err := reader.WriteResult(&out, reader.JSON_OUTPUT, reader.Result{Solution: types.UNSATISFIABLE}); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "{\"result\":\"UNSATISFIABLE\"}\n" {
+		t.Errorf("Unexpected JSON %q", out.String())
+	}
+}
+
+func TestWriteStats(t *testing.T) {
+	result := reader.Result{
+		Solution: types.UNKNOWN,
+		Stats:    &types.Stats{Conflicts: 7},
+	}
+
+	var out strings.Builder
+	if err := reader.WriteResult(&out, reader.COMPETITION_OUTPUT, result); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "c conflicts     7\n") || !strings.HasSuffix(out.String(), "s UNKNOWN\n") {
+		t.Errorf("Expected the statistics as comments before the solution, found %q", out.String())
+	}
+
+	out.Reset()
+	if err := reader.WriteResult(&out, reader.JSON_OUTPUT, result); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"stats":{"decisions":0,"propagations":0,"conflicts":7,`) {
+		t.Errorf("Expected the statistics as an object, found %q", out.String())
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	if reader.ExitCode(types.SATISFIABLE) != 10 || reader.ExitCode(types.UNSATISFIABLE) != 20 || reader.ExitCode(types.UNKNOWN) != 0 {
+		t.Errorf("Exit codes do not follow the SAT competition")
+	}
+}
@@ -13,7 +13,8 @@ var infoLogger *log.Logger  // infoLogger for general logging
 var errorLogger *log.Logger // errorlogger for exclusive error reporting
 
 func init() {
-	infoLogger = log.New(os.Stdout, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
+	// Info messages go to stderr with the errors, so that verbose runs keep stdout parseable in every format
+	infoLogger = log.New(os.Stderr, "INFO: ", log.Ldate|log.Ltime|log.Lshortfile)
 	errorLogger = log.New(os.Stderr, "ERROR: ", log.Ldate|log.Ltime|log.Lshortfile)
 }
 
@@ -0,0 +1,229 @@
+package logic
+
+/*
+The logic package writes constraints as Boolean expressions over named variables and translates
+them into CNF. Expressions are built with the functions named after their operators, or parsed from
+text, refer Parse.
+*/
+
+import (
+	"strings"
+)
+
+/*
+ExprType is an enum defining the kind of an expression
+*/
+type ExprType uint
+
+const (
+	VARIABLE_EXPR ExprType = iota // A named variable
+	TRUE_EXPR                     // The constant true
+	FALSE_EXPR                    // The constant false
+	NOT_EXPR                      // Negation of the single argument
+	AND_EXPR                      // Conjunction of the arguments, true if there are none
+	OR_EXPR                       // Disjunction of the arguments, false if there are none
+	IMPLIES_EXPR                  // The first argument implies the second
+	IFF_EXPR                      // The two arguments are equivalent
+	XOR_EXPR                      // An odd no of the arguments are true
+	ITE_EXPR                      // If the first argument then the second else the third
+)
+
+func (t ExprType) String() string {
+	switch t {
+	case VARIABLE_EXPR:
+		return "variable"
+	case TRUE_EXPR:
+		return "true"
+	case FALSE_EXPR:
+		return "false"
+	case NOT_EXPR:
+		return "!"
+	case AND_EXPR:
+		return "&"
+	case OR_EXPR:
+		return "|"
+	case IMPLIES_EXPR:
+		return "->"
+	case IFF_EXPR:
+		return "<->"
+	case XOR_EXPR:
+		return "^"
+	case ITE_EXPR:
+		return "ite"
+	}
+	return "unknown"
+}
+
+// Expr is a node of the syntax tree of a Boolean expression
+type Expr struct {
+	Type ExprType
+	Name string  // Name of a variable
+	Args []*Expr // Arguments of an operator
+}
+
+// Var returns the variable with the given name
+func Var(name string) *Expr {
+	return &Expr{Type: VARIABLE_EXPR, Name: name}
+}
+
+// Const returns the constant true or false
+func Const(value bool) *Expr {
+	if value {
+		return &Expr{Type: TRUE_EXPR}
+	}
+	return &Expr{Type: FALSE_EXPR}
+}
+
+func Not(e *Expr) *Expr {
+	return &Expr{Type: NOT_EXPR, Args: []*Expr{e}}
+}
+
+func And(args ...*Expr) *Expr {
+	return &Expr{Type: AND_EXPR, Args: args}
+}
+
+func Or(args ...*Expr) *Expr {
+	return &Expr{Type: OR_EXPR, Args: args}
+}
+
+func Implies(a, b *Expr) *Expr {
+	return &Expr{Type: IMPLIES_EXPR, Args: []*Expr{a, b}}
+}
+
+func Iff(a, b *Expr) *Expr {
+	return &Expr{Type: IFF_EXPR, Args: []*Expr{a, b}}
+}
+
+func Xor(args ...*Expr) *Expr {
+	return &Expr{Type: XOR_EXPR, Args: args}
+}
+
+// ITE returns the expression which is t if c holds and e otherwise
+func ITE(c, t, e *Expr) *Expr {
+	return &Expr{Type: ITE_EXPR, Args: []*Expr{c, t, e}}
+}
+
+// Eval returns the value of the expression, variables missing from the values are false
+func (e *Expr) Eval(values map[string]bool) bool {
+	switch e.Type {
+	case VARIABLE_EXPR:
+		return values[e.Name]
+	case TRUE_EXPR:
+		return true
+	case NOT_EXPR:
+		return !e.Args[0].Eval(values)
+	case AND_EXPR:
+		for _, a := range e.Args {
+			if !a.Eval(values) {
+				return false
+			}
+		}
+		return true
+	case OR_EXPR:
+		for _, a := range e.Args {
+			if a.Eval(values) {
+				return true
+			}
+		}
+		return false
+	case IMPLIES_EXPR:
+		return !e.Args[0].Eval(values) || e.Args[1].Eval(values)
+	case IFF_EXPR:
+		return e.Args[0].Eval(values) == e.Args[1].Eval(values)
+	case XOR_EXPR:
+		odd := false
+		for _, a := range e.Args {
+			odd = odd != a.Eval(values)
+		}
+		return odd
+	case ITE_EXPR:
+		if e.Args[0].Eval(values) {
+			return e.Args[1].Eval(values)
+		}
+		return e.Args[2].Eval(values)
+	}
+	return false
+}
+
+// Vars returns the names of the variables of the expression in the order of their first occurrence
+func (e *Expr) Vars() []string {
+	var (
+		names []string
+		seen  = make(map[string]bool)
+		visit func(e *Expr)
+	)
+	visit = func(e *Expr) {
+		if e.Type == VARIABLE_EXPR && !seen[e.Name] {
+			seen[e.Name] = true
+			names = append(names, e.Name)
+		}
+		for _, a := range e.Args {
+			visit(a)
+		}
+	}
+	visit(e)
+	return names
+}
+
+// String returns the expression in the syntax read by Parse, with arguments which are not atomic in parentheses
+func (e *Expr) String() string {
+	var b strings.Builder
+	e.write(&b)
+	return b.String()
+}
+
+func (e *Expr) write(b *strings.Builder) {
+	switch e.Type {
+	case VARIABLE_EXPR:
+		b.WriteString(e.Name)
+		return
+	case TRUE_EXPR, FALSE_EXPR:
+		b.WriteString(e.Type.String())
+		return
+	case NOT_EXPR:
+		b.WriteString("!")
+		e.Args[0].writeArgument(b)
+		return
+	case ITE_EXPR:
+		b.WriteString("ite(")
+		for i, a := range e.Args {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			a.write(b)
+		}
+		b.WriteString(")")
+		return
+	case AND_EXPR, OR_EXPR, XOR_EXPR:
+		// The empty conjunction is true, the empty disjunction and parity are false
+		if len(e.Args) == 0 && e.Type == AND_EXPR {
+			b.WriteString("true")
+			return
+		} else if len(e.Args) == 0 {
+			b.WriteString("false")
+			return
+		}
+	}
+
+	for i, a := range e.Args {
+		if i > 0 {
+			b.WriteString(" " + e.Type.String() + " ")
+		}
+		a.writeArgument(b)
+	}
+}
+
+// Writes an argument, in parentheses unless it is atomic
+func (e *Expr) writeArgument(b *strings.Builder) {
+	switch {
+	case e.Type == VARIABLE_EXPR || e.Type == TRUE_EXPR || e.Type == FALSE_EXPR || e.Type == NOT_EXPR || e.Type == ITE_EXPR:
+		e.write(b)
+	case len(e.Args) == 1 && (e.Type == AND_EXPR || e.Type == OR_EXPR || e.Type == XOR_EXPR):
+		// A single argument is written without its operator
+		e.Args[0].writeArgument(b)
+	default:
+		b.WriteString("(")
+		e.write(b)
+		b.WriteString(")")
+	}
+}
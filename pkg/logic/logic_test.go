@@ -0,0 +1,203 @@
+package logic_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	logic "github.com/alanpjohn/go-cdcl/pkg/logic"
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{"a | b & c", "a | (b & c)"},
+		{"a ^ b & c | d", "(a ^ (b & c)) | d"},
+		{"a -> b -> c", "a -> (b -> c)"},
+		{"a <-> b <-> c", "(a <-> b) <-> c"},
+		{"!~a & !(b | c)", "!!a & !(b | c)"},
+		{"ite(x[1], true, y.z) # comment", "ite(x[1], true, y.z)"},
+		{"(((a)))", "a"},
+	}
+
+	for _, c := range cases {
+		e, err := logic.ParseExpr(c.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.String() != c.expected {
+			t.Errorf("Expected %q to be read as %q, found %q", c.input, c.expected, e.String())
+		}
+		// Written expressions are read back unchanged
+		if again, err := logic.ParseExpr(e.String()); err != nil || again.String() != e.String() {
+			t.Errorf("Expected to read back %q, found %v %v", e.String(), again, err)
+		}
+	}
+
+	exprs, err := logic.Parse(strings.NewReader("a -> b;\n# comment\n!b;;\nfalse | a;"))
+	if err != nil || len(exprs) != 3 || exprs[2].String() != "false | a" {
+		t.Errorf("Expected 3 expressions, found %v %v", exprs, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		input   string
+		message string
+	}{
+		{"a &", "Line 1, column 4: expected an expression, found the end of the input"},
+		{"a b", "Line 1, column 3: expected an operator or \";\", found \"b\""},
+		{"(a | b\n", "Line 2, column 1: expected \")\""},
+		{"a\n  & $", "Line 2, column 5: unexpected character '$'"},
+		{"ite(a, b)", "Line 1, column 9: expected \",\", found \")\""},
+		{"a - b", "Line 1, column 3: unexpected character '-'"},
+	}
+
+	for _, c := range cases {
+		_, err := logic.Parse(strings.NewReader(c.input))
+		if err == nil || !strings.Contains(err.Error(), c.message) {
+			t.Errorf("Expected error %q for %q, found %v", c.message, c.input, err)
+		}
+	}
+}
+
+var names = []string{"a", "b", "c", "d"}
+
+func randomExpr(random *rand.Rand, depth int) *logic.Expr {
+	if depth == 0 || random.Intn(4) == 0 {
+		if random.Intn(10) == 0 {
+			return logic.Const(random.Intn(2) == 0)
+		}
+		return logic.Var(names[random.Intn(len(names))])
+	}
+
+	args := func(n int) []*logic.Expr {
+		out := make([]*logic.Expr, n)
+		for i := range out {
+			out[i] = randomExpr(random, depth-1)
+		}
+		return out
+	}
+	switch random.Intn(7) {
+	case 0:
+		return logic.Not(randomExpr(random, depth-1))
+	case 1:
+		return logic.And(args(random.Intn(4))...)
+	case 2:
+		return logic.Or(args(random.Intn(4))...)
+	case 3:
+		a := args(2)
+		return logic.Implies(a[0], a[1])
+	case 4:
+		a := args(2)
+		return logic.Iff(a[0], a[1])
+	case 5:
+		return logic.Xor(args(random.Intn(4))...)
+	}
+	a := args(3)
+	return logic.ITE(a[0], a[1], a[2])
+}
+
+func TestTranslate(t *testing.T) {
+	random := rand.New(rand.NewSource(6))
+
+	for n := 0; n < 300; n++ {
+		var exprs []*logic.Expr
+		for i := 1 + random.Intn(2); i > 0; i-- {
+			exprs = append(exprs, randomExpr(random, 4))
+		}
+		// Shared subexpressions are translated once for both of their polarities
+		shared := randomExpr(random, 2)
+		exprs = append(exprs, logic.Or(shared, logic.Var("a")), logic.Or(logic.Not(shared), logic.Var("b")))
+
+		for _, translation := range []logic.Translation{logic.TSEITIN_TRANSLATION, logic.PLAISTED_GREENBAUM_TRANSLATION} {
+			sat, symbols, err := logic.Translate(exprs, translation)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, err := solver.InitializeBaseSolver(sat, solver.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Every assignment of the variables extends to a model of the clauses exactly when the expressions hold
+			for bits := 0; bits < 1<<len(names); bits++ {
+				values := make(map[string]bool)
+				var assumptions []types.Literal
+				for i, name := range names {
+					values[name] = bits&(1<<i) != 0
+					if a, ok := symbols.Lookup(name); ok {
+						l := types.Literal(a)
+						if !values[name] {
+							l = l.Negate()
+						}
+						assumptions = append(assumptions, l)
+					}
+				}
+
+				expected := true
+				for _, e := range exprs {
+					expected = expected && e.Eval(values)
+				}
+				solution, err := s.Solve(assumptions...)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if expected != (solution == types.SATISFIABLE) {
+					t.Fatalf("%v translation of %v under %v: expected satisfiable %v, found %v", translation, exprs, values, expected, solution)
+				}
+				if expected {
+					model := symbols.Assignment(s.Model())
+					for _, name := range names {
+						if _, ok := symbols.Lookup(name); ok && model[name] != values[name] {
+							t.Fatalf("Expected %v to be %v in %v", name, values[name], model)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestSymbolTable(t *testing.T) {
+	e, err := logic.ParseExpr("(x -> y) & (y <-> !z) & x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sat, symbols, err := logic.Translate([]*logic.Expr{e}, logic.PLAISTED_GREENBAUM_TRANSLATION)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The variables come first, in the order of their occurrence
+	for i, name := range []string{"x", "y", "z"} {
+		if a, ok := symbols.Lookup(name); !ok || a != types.Atom(i+1) {
+			t.Errorf("Expected %v to be atom %v, found %v", name, i+1, a)
+		}
+		if n, ok := symbols.Name(types.Atom(i + 1)); !ok || n != name {
+			t.Errorf("Expected atom %v to be named %v, found %v", i+1, name, n)
+		}
+	}
+	if _, ok := symbols.Name(types.Atom(sat.AtomCount)); sat.AtomCount > 3 && ok {
+		t.Errorf("Expected auxiliary atom %v to have no name", sat.AtomCount)
+	}
+
+	s, err := solver.InitializeBaseSolver(sat, solver.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solution, err := s.Solve(); err != nil || solution != types.SATISFIABLE {
+		t.Fatalf("Expected SATISFIABLE, found %v %v", solution, err)
+	}
+	if values := symbols.Assignment(s.Model()); !values["x"] || !values["y"] || values["z"] {
+		t.Errorf("Expected x, y and !z, found %v", values)
+	}
+
+	if _, _, err = logic.Translate([]*logic.Expr{logic.Implies(logic.Var("a"), nil)}, logic.TSEITIN_TRANSLATION); err == nil {
+		t.Errorf("Expected an error for a missing argument")
+	}
+}
@@ -0,0 +1,273 @@
+package logic
+
+/*
+The parse file reads Boolean expressions from text. Expressions are separated by `;` and `#` starts a
+comment which runs to the end of the line. The operators, from the loosest to the tightest binding, are
+
+	a <-> b    equivalence, grouping to the left
+	a -> b     implication, grouping to the right
+	a | b      disjunction
+	a ^ b      exclusive or
+	a & b      conjunction
+	!a, ~a     negation
+
+along with the constants `true` and `false`, `ite(c, t, e)` for if-then-else and parentheses. Variable
+names start with a letter or `_`, followed by letters, digits, `_`, `.`, `[` and `]`.
+*/
+
+import (
+	"fmt"
+	"io"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+)
+
+// A token of the expression syntax with its position
+type token struct {
+	text   string // The operator or name, empty at the end of the input
+	line   int
+	column int
+}
+
+// parser reads expressions from tokens split from the input
+type parser struct {
+	input  []byte
+	pos    int
+	line   int
+	column int
+	next   token // The next token, not yet consumed
+}
+
+func isNameStart(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b == '_'
+}
+
+func isName(b byte) bool {
+	return isNameStart(b) || b >= '0' && b <= '9' || b == '.' || b == '[' || b == ']'
+}
+
+// Builds an error pointing at a token
+func errorAt(t token, format string, args ...interface{}) error {
+	return handler.Throw(fmt.Sprintf("Line %v, column %v: ", t.line, t.column)+fmt.Sprintf(format, args...), nil)
+}
+
+// Splits the next token from the input
+func (p *parser) scan() error {
+	// Skip whitespace and comments
+	for p.pos < len(p.input) {
+		b := p.input[p.pos]
+		switch {
+		case b == '\n':
+			p.line++
+			p.column = 1
+			p.pos++
+		case b == ' ' || b == '\t' || b == '\r':
+			p.column++
+			p.pos++
+		case b == '#':
+			for p.pos < len(p.input) && p.input[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			goto found
+		}
+	}
+	p.next = token{line: p.line, column: p.column}
+	return nil
+
+found:
+	start := p.pos
+	t := token{line: p.line, column: p.column}
+	switch b := p.input[p.pos]; {
+	case isNameStart(b):
+		for p.pos < len(p.input) && isName(p.input[p.pos]) {
+			p.pos++
+		}
+	case b == '-' && p.pos+1 < len(p.input) && p.input[p.pos+1] == '>':
+		p.pos += 2
+	case b == '<' && p.pos+2 < len(p.input) && p.input[p.pos+1] == '-' && p.input[p.pos+2] == '>':
+		p.pos += 3
+	case b == '(' || b == ')' || b == ',' || b == ';' || b == '!' || b == '~' || b == '&' || b == '|' || b == '^':
+		p.pos++
+	default:
+		return errorAt(t, "unexpected character %q", b)
+	}
+	t.text = string(p.input[start:p.pos])
+	p.column += p.pos - start
+	p.next = t
+	return nil
+}
+
+// Consumes the next token, which must be the given one
+func (p *parser) expect(text string) error {
+	if p.next.text != text {
+		return p.unexpected("expected %q", text)
+	}
+	return p.scan()
+}
+
+// Builds an error for the next token
+func (p *parser) unexpected(format string, args ...interface{}) error {
+	found := fmt.Sprintf("%q", p.next.text)
+	if p.next.text == "" {
+		found = "the end of the input"
+	}
+	return errorAt(p.next, fmt.Sprintf(format, args...)+", found "+found)
+}
+
+func (p *parser) iff() (*Expr, error) {
+	e, err := p.implies()
+	for err == nil && p.next.text == "<->" {
+		var right *Expr
+		if err = p.scan(); err != nil {
+			return nil, err
+		}
+		if right, err = p.implies(); err == nil {
+			e = Iff(e, right)
+		}
+	}
+	return e, err
+}
+
+func (p *parser) implies() (*Expr, error) {
+	e, err := p.binary(OR_EXPR)
+	if err != nil || p.next.text != "->" {
+		return e, err
+	}
+	if err = p.scan(); err != nil {
+		return nil, err
+	}
+	right, err := p.implies()
+	if err != nil {
+		return nil, err
+	}
+	return Implies(e, right), nil
+}
+
+// Parses the arguments of an n-ary operator, each of them bound tighter than the operator
+func (p *parser) binary(op ExprType) (*Expr, error) {
+	tighter := map[ExprType]ExprType{OR_EXPR: XOR_EXPR, XOR_EXPR: AND_EXPR}
+	operand := func() (*Expr, error) {
+		if next, ok := tighter[op]; ok {
+			return p.binary(next)
+		}
+		return p.unary()
+	}
+
+	e, err := operand()
+	if err != nil || p.next.text != op.String() {
+		return e, err
+	}
+	args := []*Expr{e}
+	for p.next.text == op.String() {
+		if err = p.scan(); err != nil {
+			return nil, err
+		}
+		if e, err = operand(); err != nil {
+			return nil, err
+		}
+		args = append(args, e)
+	}
+	return &Expr{Type: op, Args: args}, nil
+}
+
+func (p *parser) unary() (*Expr, error) {
+	if p.next.text == "!" || p.next.text == "~" {
+		if err := p.scan(); err != nil {
+			return nil, err
+		}
+		e, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(e), nil
+	}
+	return p.primary()
+}
+
+func (p *parser) primary() (*Expr, error) {
+	t := p.next
+	switch {
+	case t.text == "(":
+		if err := p.scan(); err != nil {
+			return nil, err
+		}
+		e, err := p.iff()
+		if err != nil {
+			return nil, err
+		}
+		return e, p.expect(")")
+	case t.text == "true" || t.text == "false":
+		return Const(t.text == "true"), p.scan()
+	case t.text == "ite":
+		if err := p.scan(); err != nil {
+			return nil, err
+		}
+		var args [3]*Expr
+		for i, sep := range []string{"(", ",", ","} {
+			if err := p.expect(sep); err != nil {
+				return nil, err
+			}
+			e, err := p.iff()
+			if err != nil {
+				return nil, err
+			}
+			args[i] = e
+		}
+		return ITE(args[0], args[1], args[2]), p.expect(")")
+	case t.text != "" && isNameStart(t.text[0]):
+		return Var(t.text), p.scan()
+	}
+	return nil, p.unexpected("expected an expression")
+}
+
+/*
+Parse reads expressions separated by `;` from the input stream, refer the syntax above. Errors give
+the line and column of the offending token.
+*/
+func Parse(r io.Reader) ([]*Expr, error) {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return nil, handler.Throw("The expressions could not be read", err)
+	}
+
+	p := &parser{input: input, line: 1, column: 1}
+	if err = p.scan(); err != nil {
+		return nil, err
+	}
+
+	var exprs []*Expr
+	for p.next.text != "" {
+		if p.next.text == ";" {
+			if err = p.scan(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		e, err := p.iff()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+		if p.next.text != "" && p.next.text != ";" {
+			return nil, p.unexpected("expected an operator or \";\"")
+		}
+	}
+	return exprs, nil
+}
+
+// ParseExpr reads a single expression from a string
+func ParseExpr(s string) (*Expr, error) {
+	p := &parser{input: []byte(s), line: 1, column: 1}
+	if err := p.scan(); err != nil {
+		return nil, err
+	}
+	e, err := p.iff()
+	if err != nil {
+		return nil, err
+	}
+	if p.next.text != "" {
+		return nil, p.unexpected("expected an operator")
+	}
+	return e, nil
+}
@@ -0,0 +1,462 @@
+package logic
+
+/*
+The translate file turns expressions into clauses. Every subexpression which is not a literal gets a
+fresh atom standing for its value, with clauses defining the atom in terms of the atoms of its arguments.
+
+The Tseitin translation defines the atoms of subexpressions by equivalences. The Plaisted-Greenbaum
+translation only encodes the direction of the equivalence its occurrence needs: an atom occurring
+positively only has to imply its subexpression, an atom occurring negatively only has to be implied by
+it. Atoms of equivalences, parities and if-then-else conditions occur both ways. Both translations are
+satisfiable exactly for the models of the expressions, the Plaisted-Greenbaum translation with about
+half the clauses.
+*/
+
+import (
+	"fmt"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+Translation is an enum defining how expressions are translated into clauses
+*/
+type Translation uint
+
+const (
+	TSEITIN_TRANSLATION            Translation = iota // Atoms of subexpressions are equivalent to them
+	PLAISTED_GREENBAUM_TRANSLATION                    // Atoms of subexpressions only imply them as far as their polarity needs
+)
+
+func (t Translation) String() string {
+	switch t {
+	case TSEITIN_TRANSLATION:
+		return "tseitin"
+	case PLAISTED_GREENBAUM_TRANSLATION:
+		return "plaisted-greenbaum"
+	}
+	return "unknown"
+}
+
+/*
+SymbolTable maps the names of variables to atoms. Atoms are numbered from 1 in the order names are
+added, auxiliary atoms of the translation are numbered along with them and have no name.
+*/
+type SymbolTable struct {
+	AtomCount uint                  // No of atoms, named and auxiliary
+	atoms     map[string]types.Atom // Atom of every name
+	names     map[types.Atom]string // Name of every named atom
+}
+
+func ConstructSymbolTable() *SymbolTable {
+	return &SymbolTable{atoms: make(map[string]types.Atom), names: make(map[types.Atom]string)}
+}
+
+// Atom returns the atom of the name, a new atom is given to a name seen for the first time
+func (s *SymbolTable) Atom(name string) types.Atom {
+	if a, ok := s.atoms[name]; ok {
+		return a
+	}
+	a := s.Fresh()
+	s.atoms[name] = a
+	s.names[a] = name
+	return a
+}
+
+// Lookup returns the atom of the name, if it has one
+func (s *SymbolTable) Lookup(name string) (types.Atom, bool) {
+	a, ok := s.atoms[name]
+	return a, ok
+}
+
+// Name returns the name of the atom, auxiliary atoms have none
+func (s *SymbolTable) Name(a types.Atom) (string, bool) {
+	name, ok := s.names[a]
+	return name, ok
+}
+
+// Fresh returns a new atom without a name
+func (s *SymbolTable) Fresh() types.Atom {
+	s.AtomCount++
+	return types.Atom(s.AtomCount)
+}
+
+/*
+Assignment returns the value of every named variable in a model, where model[i] is the literal of
+atom i+1 as returned by the solver. Variables whose atom is beyond the model are false.
+*/
+func (s *SymbolTable) Assignment(model []types.Literal) map[string]bool {
+	values := make(map[string]bool, len(s.atoms))
+	for name, a := range s.atoms {
+		values[name] = int(a) <= len(model) && model[a-1] > 0
+	}
+	return values
+}
+
+// Polarities in which an atom of a subexpression occurs, as a bitset
+type polarity uint8
+
+const (
+	positive polarity = 1 << iota // The atom has to imply the subexpression
+	negative                      // The atom has to be implied by the subexpression
+	both     = positive | negative
+)
+
+// Swaps the polarities, as the argument of a negation occurs with the opposite polarity
+func (p polarity) flip() polarity {
+	return p&positive<<1 | p&negative>>1
+}
+
+// Atom of a subexpression and the polarities its clauses were added for
+type definition struct {
+	literal types.Literal
+	defined polarity
+}
+
+/*
+Translator adds expressions to a SATFile. Names are mapped to atoms by the Symbols, which can be
+shared with other Translators of the same formula. A subexpression shared by several expressions,
+as the same *Expr, is translated once.
+*/
+type Translator struct {
+	Translation Translation
+	Symbols     *SymbolTable
+	clauses     []types.Disjunction
+	definitions map[*Expr]*definition
+}
+
+func ConstructTranslator(translation Translation, symbols *SymbolTable) *Translator {
+	return &Translator{Translation: translation, Symbols: symbols, definitions: make(map[*Expr]*definition)}
+}
+
+// SATFile returns the clauses of the expressions added so far
+func (t *Translator) SATFile() types.SATFile {
+	return types.SATFile{
+		AtomCount:   t.Symbols.AtomCount,
+		ClauseCount: uint(len(t.clauses)),
+		Clauses:     t.clauses,
+	}
+}
+
+/*
+Assert adds clauses which hold exactly when the expression is true. Constants are simplified away
+first, a false expression adds the empty clause. Conjunctions at the top are split into their
+arguments and disjunctions at the top become a single clause.
+*/
+func (t *Translator) Assert(e *Expr) error {
+	if err := check(e); err != nil {
+		return err
+	}
+	t.assert(simplify(e))
+	return nil
+}
+
+func (t *Translator) assert(e *Expr) {
+	switch e.Type {
+	case TRUE_EXPR:
+	case FALSE_EXPR:
+		t.clauses = append(t.clauses, types.Disjunction{})
+	case AND_EXPR:
+		for _, a := range e.Args {
+			t.assert(a)
+		}
+	case OR_EXPR:
+		clause := make(types.Disjunction, len(e.Args))
+		for i, a := range e.Args {
+			clause[i] = t.translate(a, positive)
+		}
+		t.clauses = append(t.clauses, clause)
+	default:
+		t.clauses = append(t.clauses, types.Disjunction{t.translate(e, positive)})
+	}
+}
+
+// Checks that every operator has a valid no of arguments and every variable a name
+func check(e *Expr) error {
+	if e == nil {
+		return handler.Throw("Missing expression", nil)
+	}
+	expected := -1
+	switch e.Type {
+	case VARIABLE_EXPR:
+		if e.Name == "" {
+			return handler.Throw("Variable without a name", nil)
+		}
+		expected = 0
+	case TRUE_EXPR, FALSE_EXPR:
+		expected = 0
+	case NOT_EXPR:
+		expected = 1
+	case IMPLIES_EXPR, IFF_EXPR:
+		expected = 2
+	case ITE_EXPR:
+		expected = 3
+	case AND_EXPR, OR_EXPR, XOR_EXPR:
+	default:
+		return handler.Throw(fmt.Sprintf("Unknown expression type %v", uint(e.Type)), nil)
+	}
+	if expected >= 0 && len(e.Args) != expected {
+		return handler.Throw(fmt.Sprintf("%v takes %v arguments, found %v", e.Type, expected, len(e.Args)), nil)
+	}
+	for _, a := range e.Args {
+		if err := check(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+Returns an equivalent expression without constants, unless it is a constant itself. Implications
+become disjunctions and nested conjunctions and disjunctions are flattened. Subexpressions without
+constants keep their identity, so that shared subexpressions stay shared.
+*/
+func simplify(e *Expr) *Expr {
+	args := make([]*Expr, len(e.Args))
+	changed := false
+	for i, a := range e.Args {
+		args[i] = simplify(a)
+		changed = changed || args[i] != a
+	}
+	isConst := func(e *Expr) bool { return e.Type == TRUE_EXPR || e.Type == FALSE_EXPR }
+
+	switch e.Type {
+	case NOT_EXPR:
+		if isConst(args[0]) {
+			return Const(args[0].Type == FALSE_EXPR)
+		}
+		if args[0].Type == NOT_EXPR {
+			return args[0].Args[0]
+		}
+	case IMPLIES_EXPR:
+		return simplify(Or(Not(args[0]), args[1]))
+	case AND_EXPR, OR_EXPR:
+		// The absorbing constant is false for conjunctions and true for disjunctions
+		absorbing := FALSE_EXPR
+		if e.Type == OR_EXPR {
+			absorbing = TRUE_EXPR
+		}
+		var kept []*Expr
+		for _, a := range args {
+			switch {
+			case a.Type == absorbing:
+				return Const(absorbing == TRUE_EXPR)
+			case isConst(a):
+				changed = true
+			case a.Type == e.Type:
+				kept = append(kept, a.Args...)
+				changed = true
+			default:
+				kept = append(kept, a)
+			}
+		}
+		switch {
+		case len(kept) == 0:
+			return Const(absorbing != TRUE_EXPR)
+		case len(kept) == 1:
+			return kept[0]
+		case changed:
+			return &Expr{Type: e.Type, Args: kept}
+		}
+		return e
+	case XOR_EXPR, IFF_EXPR:
+		// Constants flip the parity, an equivalence is the negated parity of its arguments
+		odd := e.Type == IFF_EXPR
+		var kept []*Expr
+		for _, a := range args {
+			if isConst(a) {
+				odd = odd != (a.Type == TRUE_EXPR)
+				changed = true
+			} else {
+				kept = append(kept, a)
+			}
+		}
+		var out *Expr
+		switch {
+		case len(kept) == 0:
+			return Const(odd)
+		case len(kept) == 1:
+			out = kept[0]
+		case !changed:
+			return e
+		case e.Type == IFF_EXPR:
+			out = Iff(kept[0], kept[1])
+			odd = !odd
+		default:
+			out = Xor(kept...)
+		}
+		if odd {
+			return simplify(Not(out))
+		}
+		return out
+	case ITE_EXPR:
+		if isConst(args[0]) {
+			if args[0].Type == TRUE_EXPR {
+				return args[1]
+			}
+			return args[2]
+		}
+		if isConst(args[1]) || isConst(args[2]) {
+			c, a, b := args[0], args[1], args[2]
+			return simplify(Or(And(c, a), And(Not(c), b)))
+		}
+	}
+
+	if changed {
+		return &Expr{Type: e.Type, Name: e.Name, Args: args}
+	}
+	return e
+}
+
+/*
+Returns the literal standing for the expression, adding the clauses which define it for the given
+polarities. The expression has no constants.
+*/
+func (t *Translator) translate(e *Expr, p polarity) types.Literal {
+	switch e.Type {
+	case VARIABLE_EXPR:
+		return types.Literal(t.Symbols.Atom(e.Name))
+	case NOT_EXPR:
+		return t.translate(e.Args[0], p.flip()).Negate()
+	}
+
+	if t.Translation == TSEITIN_TRANSLATION {
+		p = both
+	}
+	d, ok := t.definitions[e]
+	if !ok {
+		d = &definition{literal: types.Literal(t.Symbols.Fresh())}
+		t.definitions[e] = d
+	}
+	needed := p &^ d.defined
+	if needed == 0 {
+		return d.literal
+	}
+	d.defined |= needed
+	x := d.literal
+
+	switch e.Type {
+	case AND_EXPR, OR_EXPR:
+		args := make([]types.Literal, len(e.Args))
+		for i, a := range e.Args {
+			args[i] = t.translate(a, needed)
+		}
+		// A disjunction is the negation of the conjunction of the negated arguments
+		out := x
+		if e.Type == OR_EXPR {
+			out = x.Negate()
+			for i := range args {
+				args[i] = args[i].Negate()
+			}
+		}
+		// The polarity of the conjunction is flipped for a disjunction
+		conjunction := needed
+		if e.Type == OR_EXPR {
+			conjunction = needed.flip()
+		}
+		if conjunction&positive != 0 {
+			for _, a := range args {
+				t.clauses = append(t.clauses, types.Disjunction{out.Negate(), a})
+			}
+		}
+		if conjunction&negative != 0 {
+			clause := types.Disjunction{out}
+			for _, a := range args {
+				clause = append(clause, a.Negate())
+			}
+			t.clauses = append(t.clauses, clause)
+		}
+	case IFF_EXPR:
+		a, b := t.translate(e.Args[0], both), t.translate(e.Args[1], both)
+		t.parity(x.Negate(), []types.Literal{a, b}, needed.flip())
+	case XOR_EXPR:
+		args := make([]types.Literal, len(e.Args))
+		for i, a := range e.Args {
+			args[i] = t.translate(a, both)
+		}
+		t.parity(x, args, needed)
+	case ITE_EXPR:
+		c := t.translate(e.Args[0], both)
+		a, b := t.translate(e.Args[1], needed), t.translate(e.Args[2], needed)
+		if needed&positive != 0 {
+			t.clauses = append(t.clauses,
+				types.Disjunction{x.Negate(), c.Negate(), a},
+				types.Disjunction{x.Negate(), c, b},
+				types.Disjunction{x.Negate(), a, b},
+			)
+		}
+		if needed&negative != 0 {
+			t.clauses = append(t.clauses,
+				types.Disjunction{x, c.Negate(), a.Negate()},
+				types.Disjunction{x, c, b.Negate()},
+				types.Disjunction{x, a.Negate(), b.Negate()},
+			)
+		}
+	}
+	return x
+}
+
+/*
+Adds the clauses defining x as the parity of the arguments for the given polarities. Long parities
+are split into a chain of parities of two arguments, whose atoms occur both ways.
+*/
+func (t *Translator) parity(x types.Literal, args []types.Literal, p polarity) {
+	for len(args) > 2 {
+		y := types.Literal(t.Symbols.Fresh())
+		t.parity(y, args[:2], both)
+		args = append([]types.Literal{y}, args[2:]...)
+	}
+	if len(args) == 1 {
+		// x ↔ a
+		args = append(args, 0)
+	}
+
+	a, b := args[0], args[1]
+	if b == 0 {
+		if p&positive != 0 {
+			t.clauses = append(t.clauses, types.Disjunction{x.Negate(), a})
+		}
+		if p&negative != 0 {
+			t.clauses = append(t.clauses, types.Disjunction{x, a.Negate()})
+		}
+		return
+	}
+	if p&positive != 0 {
+		t.clauses = append(t.clauses,
+			types.Disjunction{x.Negate(), a, b},
+			types.Disjunction{x.Negate(), a.Negate(), b.Negate()},
+		)
+	}
+	if p&negative != 0 {
+		t.clauses = append(t.clauses,
+			types.Disjunction{x, a.Negate(), b},
+			types.Disjunction{x, a, b.Negate()},
+		)
+	}
+}
+
+/*
+Translate translates the expressions into a SATFile which is satisfiable exactly when they all hold.
+The variables are numbered in the order of their first occurrence before the auxiliary atoms, and
+the SymbolTable maps their names to the atoms.
+*/
+func Translate(exprs []*Expr, translation Translation) (types.SATFile, *SymbolTable, error) {
+	symbols := ConstructSymbolTable()
+	for _, e := range exprs {
+		if err := check(e); err != nil {
+			return types.SATFile{}, symbols, err
+		}
+		for _, name := range e.Vars() {
+			symbols.Atom(name)
+		}
+	}
+
+	t := ConstructTranslator(translation, symbols)
+	for _, e := range exprs {
+		if err := t.Assert(e); err != nil {
+			return types.SATFile{}, symbols, err
+		}
+	}
+	return t.SATFile(), symbols, nil
+}
@@ -0,0 +1,284 @@
+/*
+The maxsat package solves weighted partial MaxSAT problems on top of the incremental BaseCDCLSolver
+*/
+package maxsat
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+// Most times a core is trimmed by solving under it again
+const TRIM_ROUNDS = 3
+
+/*
+MaxSATSolver minimizes the total weight of the violated soft clauses with the core-guided OLL algorithm
+of Morgado, Dodaro and Marques-Silva.
+
+Every soft clause is turned into an assumption which holds if the clause is satisfied, a unit soft clause
+is its own literal and other soft clauses get a relaxation atom. Solving under the assumptions gives a core
+of assumptions which cannot all hold. The least weight w in the core is added to the lower bound and taken
+off every assumption of the core, and a Totalizer over the core lets a single one of them fail at no further
+cost, while every other failing one costs w. A model satisfying all the assumptions is then optimal.
+
+The search is stratified, assumptions with less weight than a threshold are left out till a model
+satisfies the others, and cores are trimmed by solving again under them.
+*/
+type MaxSATSolver struct {
+	SAT        *solver.BaseCDCLSolver // Holds the hard clauses, the relaxed soft clauses and the totalizers
+	WCNF       types.WCNFFile         // Problem being solved, used to find the cost of models
+	LowerBound uint64                 // Every model costs at least this much
+	Cost       uint64                 // Cost of the best model found, only valid if there is a model
+	Improved   func(cost uint64)      // Called whenever a better model is found, may be nil
+
+	weights    map[types.Literal]uint64     // Weight left on every assumption
+	totalizers map[types.Literal]*Totalizer // Totalizer whose bound is raised once the assumption fails
+	atoms      uint                         // No of atoms in the SAT solver
+	model      []types.Literal              // Best model found, nil if there is none
+}
+
+// Initializes the SAT solver with the hard clauses of the WCNFFile and turns the soft clauses into assumptions
+func InitializeMaxSATSolver(wcnf types.WCNFFile, options solver.Options) (*MaxSATSolver, error) {
+	if options.Core || options.Proof != nil {
+		return nil, handler.Throw("Cores and proofs are not available for MaxSAT problems", nil)
+	}
+
+	// The costs of models are sums of weights, which must not wrap around
+	var total uint64
+	for _, w := range wcnf.Weights {
+		if w > math.MaxInt64-total {
+			return nil, handler.Throw("The weights of the soft clauses overflow", nil)
+		}
+		total += w
+	}
+
+	sat, err := solver.InitializeBaseSolver(types.SATFile{
+		AtomCount:   wcnf.AtomCount,
+		ClauseCount: uint(len(wcnf.Hard)),
+		Clauses:     wcnf.Hard,
+	}, options)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &MaxSATSolver{
+		SAT:        &sat,
+		WCNF:       wcnf,
+		weights:    make(map[types.Literal]uint64),
+		totalizers: make(map[types.Literal]*Totalizer),
+		atoms:      wcnf.AtomCount,
+	}
+
+	for i, d := range wcnf.Soft {
+		switch len(d) {
+		case 0:
+			// An empty soft clause is violated by every model
+			m.LowerBound += wcnf.Weights[i]
+		case 1:
+			m.weights[d[0]] += wcnf.Weights[i]
+		default:
+			relax := m.fresh()
+			if err = m.SAT.AddClause(append(append(types.Disjunction{}, d...), relax)); err != nil {
+				return nil, err
+			}
+			m.weights[relax.Negate()] += wcnf.Weights[i]
+		}
+	}
+
+	return m, nil
+}
+
+// Returns a new atom of the SAT solver as a positive literal
+func (m *MaxSATSolver) fresh() types.Literal {
+	m.atoms++
+	return types.Literal(m.atoms)
+}
+
+/*
+Solve searches for a model of the hard clauses with the least cost.
+
+It returns OPTIMUM once the best model is found, UNSATISFIABLE if the hard clauses cannot be satisfied,
+and SATISFIABLE with the best model so far if the context or the limits of the SAT solver stop the search.
+*/
+func (m *MaxSATSolver) Solve(ctx context.Context) (types.Solution, error) {
+	// A first model of the hard clauses bounds the cost from above
+	solution, err := m.SAT.SolveContext(ctx)
+	if err != nil || solution != types.SATISFIABLE {
+		return solution, err
+	}
+	m.update()
+
+	// Stratification: assumptions of higher weight are solved for first
+	threshold := m.next(0)
+	for m.Cost > m.LowerBound {
+		solution, err = m.SAT.SolveContext(ctx, m.assumptions(threshold)...)
+		if err != nil {
+			return types.UNKNOWN, err
+		}
+
+		switch solution {
+		case types.SATISFIABLE:
+			m.update()
+			if threshold = m.next(threshold); threshold == 0 {
+				// Every assumption holds
+				return types.OPTIMUM, nil
+			}
+			continue
+		case types.UNKNOWN:
+			return types.SATISFIABLE, nil
+		}
+
+		core, err := m.trim(ctx, m.SAT.Failed())
+		if err != nil {
+			return types.UNKNOWN, err
+		}
+		if len(core) == 0 {
+			return types.UNKNOWN, handler.Throw("The hard clauses became unsatisfiable after a model was found", nil)
+		}
+		if err = m.relax(core); err != nil {
+			return types.UNKNOWN, err
+		}
+	}
+
+	return types.OPTIMUM, nil
+}
+
+/*
+Returns the largest weight of an assumption below the threshold, 0 if there is none.
+A threshold of 0 gives the largest weight of all.
+*/
+func (m *MaxSATSolver) next(threshold uint64) uint64 {
+	var weight uint64
+	for _, w := range m.weights {
+		if (threshold == 0 || w < threshold) && w > weight {
+			weight = w
+		}
+	}
+	return weight
+}
+
+// Solving again under the core gives smaller cores, which is repeated while the core shrinks
+func (m *MaxSATSolver) trim(ctx context.Context, core []types.Literal) ([]types.Literal, error) {
+	for i := 0; i < TRIM_ROUNDS && len(core) > 1; i++ {
+		solution, err := m.SAT.SolveContext(ctx, core...)
+		if err != nil || solution != types.UNSATISFIABLE {
+			// The core so far is still a core
+			return core, err
+		}
+		trimmed := m.SAT.Failed()
+		if len(trimmed) == len(core) {
+			break
+		}
+		core = trimmed
+	}
+	return core, nil
+}
+
+// Returns the assumptions with at least the threshold of weight left, sorted so that the search is reproducible
+func (m *MaxSATSolver) assumptions(threshold uint64) []types.Literal {
+	assumptions := make([]types.Literal, 0, len(m.weights))
+	for l, w := range m.weights {
+		if w >= threshold {
+			assumptions = append(assumptions, l)
+		}
+	}
+	sort.Slice(assumptions, func(i, j int) bool { return assumptions[i] < assumptions[j] })
+	return assumptions
+}
+
+// Raises the lower bound by the least weight of the core and lets one assumption of the core fail for free
+func (m *MaxSATSolver) relax(core []types.Literal) error {
+	least := m.weights[core[0]]
+	for _, a := range core {
+		if m.weights[a] < least {
+			least = m.weights[a]
+		}
+	}
+	m.LowerBound += least
+	logger.Info(fmt.Sprintf("Core of %v assumptions with weight %v, lower bound %v", len(core), least, m.LowerBound))
+
+	for _, a := range core {
+		if t, ok := m.totalizers[a]; ok {
+			// More inputs of the totalizer may be true, each of them costing its weight
+			delete(m.totalizers, a)
+			if t.Bound+1 < len(t.Outputs) {
+				t.Bound++
+				next := t.Outputs[t.Bound].Negate()
+				m.weights[next] += t.Weight
+				m.totalizers[next] = t
+			}
+		}
+
+		m.weights[a] -= least
+		if m.weights[a] == 0 {
+			delete(m.weights, a)
+		}
+	}
+
+	if len(core) == 1 {
+		// The assumption is false in every model
+		return m.SAT.AddClause(types.Disjunction{core[0].Negate()})
+	}
+
+	inputs := make([]types.Literal, len(core))
+	for i, a := range core {
+		inputs[i] = a.Negate()
+	}
+	t, err := ConstructTotalizer(inputs, m.fresh, m.SAT.AddClause)
+	if err != nil {
+		return err
+	}
+	t.Weight = least
+	t.Bound = 1
+
+	next := t.Outputs[1].Negate()
+	m.weights[next] += least
+	m.totalizers[next] = t
+	return nil
+}
+
+// Keeps the model of the SAT solver if it is cheaper than the best model so far
+func (m *MaxSATSolver) update() {
+	model := m.SAT.Model()[:m.WCNF.AtomCount]
+	cost := Cost(m.WCNF, model)
+	if m.model != nil && cost >= m.Cost {
+		return
+	}
+
+	m.model = append([]types.Literal{}, model...)
+	m.Cost = cost
+	logger.Info(fmt.Sprintf("Found a model of cost %v", cost))
+	if m.Improved != nil {
+		m.Improved(cost)
+	}
+}
+
+// Model returns the best model found, nil if there is none. Model()[i] is the literal of atom i+1.
+func (m *MaxSATSolver) Model() []types.Literal {
+	return m.model
+}
+
+// Cost returns the total weight of the soft clauses violated by the model
+func Cost(wcnf types.WCNFFile, model []types.Literal) uint64 {
+	var cost uint64
+	for i, d := range wcnf.Soft {
+		satisfied := false
+		for _, l := range d {
+			if int(l.Atom()) <= len(model) && model[l.Atom()-1] == l {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			cost += wcnf.Weights[i]
+		}
+	}
+	return cost
+}
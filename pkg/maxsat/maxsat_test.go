@@ -0,0 +1,130 @@
+package maxsat_test
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	maxsat "github.com/alanpjohn/go-cdcl/pkg/maxsat"
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+// Finds the least cost of a model of the hard clauses by trying every assignment, false if there is no model
+func bruteForce(wcnf types.WCNFFile) (uint64, bool) {
+	var (
+		best  uint64
+		found bool
+	)
+	model := make([]types.Literal, wcnf.AtomCount)
+	for bits := 0; bits < 1<<wcnf.AtomCount; bits++ {
+		for i := range model {
+			model[i] = types.Literal(i + 1)
+			if bits&(1<<i) == 0 {
+				model[i] = model[i].Negate()
+			}
+		}
+		violated, _ := solver.VerifyModel(types.SATFile{AtomCount: wcnf.AtomCount, Clauses: wcnf.Hard}, model)
+		if len(violated) > 0 {
+			continue
+		}
+		if cost := maxsat.Cost(wcnf, model); !found || cost < best {
+			best, found = cost, true
+		}
+	}
+	return best, found
+}
+
+func randomClause(random *rand.Rand, atoms int) types.Disjunction {
+	clause := make(types.Disjunction, 1+random.Intn(3))
+	for i := range clause {
+		clause[i] = types.Literal(1 + random.Intn(atoms))
+		if random.Intn(2) == 0 {
+			clause[i] = clause[i].Negate()
+		}
+	}
+	return clause
+}
+
+func TestMaxSAT(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+
+	for n := 0; n < 200; n++ {
+		atoms := 3 + random.Intn(6)
+		wcnf := types.WCNFFile{AtomCount: uint(atoms)}
+		for i := random.Intn(2 * atoms); i > 0; i-- {
+			wcnf.Hard = append(wcnf.Hard, randomClause(random, atoms))
+		}
+		for i := 1 + random.Intn(3*atoms); i > 0; i-- {
+			wcnf.Soft = append(wcnf.Soft, randomClause(random, atoms))
+			wcnf.Weights = append(wcnf.Weights, uint64(1+random.Intn(10)))
+		}
+
+		m, err := maxsat.InitializeMaxSATSolver(wcnf, solver.Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var improved []uint64
+		m.Improved = func(cost uint64) { improved = append(improved, cost) }
+
+		solution, err := m.Solve(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		best, found := bruteForce(wcnf)
+		if !found {
+			if solution != types.UNSATISFIABLE {
+				t.Errorf("%+v: expected UNSATISFIABLE, found %v", wcnf, solution)
+			}
+			continue
+		}
+		if solution != types.OPTIMUM || m.Cost != best || m.LowerBound != best {
+			t.Errorf("%+v: expected the optimum %v, found %v with cost %v and lower bound %v", wcnf, best, solution, m.Cost, m.LowerBound)
+			continue
+		}
+		if cost := maxsat.Cost(wcnf, m.Model()); cost != best {
+			t.Errorf("%+v: the model %v costs %v, not %v", wcnf, m.Model(), cost, best)
+		}
+		if len(improved) == 0 || improved[len(improved)-1] != best {
+			t.Errorf("Expected the last improvement to be %v, found %v", best, improved)
+		}
+	}
+}
+
+func TestMaxSATStopped(t *testing.T) {
+	wcnf := types.WCNFFile{
+		AtomCount: 2,
+		Soft:      []types.Disjunction{{1}, {-1}, {2}},
+		Weights:   []uint64{1, 2, 3},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	m, err := maxsat.InitializeMaxSATSolver(wcnf, solver.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solution, err := m.Solve(ctx); solution != types.UNKNOWN || err != nil || m.Model() != nil {
+		t.Errorf("Expected UNKNOWN without a model, found %v %v %v", solution, m.Model(), err)
+	}
+
+	if _, err = maxsat.InitializeMaxSATSolver(wcnf, solver.Options{Core: true}); err == nil {
+		t.Errorf("Expected an error for cores with MaxSAT")
+	}
+}
+
+func TestMaxSATOverflow(t *testing.T) {
+	// The model x1 = 1, x2 = 0 would cost 2^64+1, which wraps around to 1
+	wcnf := types.WCNFFile{
+		AtomCount: 2,
+		Hard:      []types.Disjunction{{1, 2}},
+		Soft:      []types.Disjunction{{-1}, {-1}, {-1}, {-2}},
+		Weights:   []uint64{math.MaxInt64, math.MaxInt64, 3, 3},
+	}
+	if _, err := maxsat.InitializeMaxSATSolver(wcnf, solver.Options{}); err == nil {
+		t.Errorf("Expected the weights %v to overflow", wcnf.Weights)
+	}
+}
@@ -0,0 +1,75 @@
+package maxsat
+
+/*
+The totalizer file counts how many of a set of literals are true with a unary adder tree, as
+introduced by Bailleux and Boufkhad. Only the clauses pushing counts up the tree are added, which is
+enough to bound the count from above with assumptions on its outputs.
+*/
+
+import (
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+Totalizer counts the true literals of its inputs. Outputs[j] is implied once j+1 inputs are true, so
+assuming the negation of Outputs[j] allows at most j true inputs.
+*/
+type Totalizer struct {
+	Outputs []types.Literal
+	Weight  uint64 // Cost of every true input after the first in the objective
+	Bound   int    // Outputs[Bound] is the output currently assumed false
+}
+
+/*
+Builds a Totalizer over the inputs. New atoms are taken from fresh and the clauses of the adder tree
+are passed to add.
+*/
+func ConstructTotalizer(inputs []types.Literal, fresh func() types.Literal, add func(types.Disjunction) error) (*Totalizer, error) {
+	outputs, err := count(inputs, fresh, add)
+	if err != nil {
+		return nil, err
+	}
+	return &Totalizer{Outputs: outputs}, nil
+}
+
+// Builds the subtree counting the inputs and returns its outputs
+func count(inputs []types.Literal, fresh func() types.Literal, add func(types.Disjunction) error) ([]types.Literal, error) {
+	if len(inputs) == 1 {
+		return inputs, nil
+	}
+
+	left, err := count(inputs[:len(inputs)/2], fresh, add)
+	if err != nil {
+		return nil, err
+	}
+	right, err := count(inputs[len(inputs)/2:], fresh, add)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := make([]types.Literal, len(left)+len(right))
+	for i := range outputs {
+		outputs[i] = fresh()
+	}
+
+	// i true inputs on the left and j on the right make at least i+j true inputs
+	for i := 0; i <= len(left); i++ {
+		for j := 0; j <= len(right); j++ {
+			if i+j == 0 {
+				continue
+			}
+			clause := types.Disjunction{outputs[i+j-1]}
+			if i > 0 {
+				clause = append(clause, left[i-1].Negate())
+			}
+			if j > 0 {
+				clause = append(clause, right[j-1].Negate())
+			}
+			if err = add(clause); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return outputs, nil
+}
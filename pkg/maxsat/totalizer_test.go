@@ -0,0 +1,61 @@
+package maxsat_test
+
+import (
+	"testing"
+
+	maxsat "github.com/alanpjohn/go-cdcl/pkg/maxsat"
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestTotalizer(t *testing.T) {
+	const inputs = 5
+
+	atoms := uint(inputs)
+	var clauses []types.Disjunction
+	fresh := func() types.Literal {
+		atoms++
+		return types.Literal(atoms)
+	}
+	add := func(d types.Disjunction) error {
+		clauses = append(clauses, d)
+		return nil
+	}
+
+	literals := make([]types.Literal, inputs)
+	for i := range literals {
+		literals[i] = types.Literal(i + 1)
+	}
+	totalizer, err := maxsat.ConstructTotalizer(literals, fresh, add)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(totalizer.Outputs) != inputs {
+		t.Fatalf("Expected %v outputs, found %v", inputs, len(totalizer.Outputs))
+	}
+
+	// Outputs[k-1] can only be false if less than k inputs are true
+	for bits := 0; bits < 1<<inputs; bits++ {
+		sat := types.SATFile{AtomCount: atoms, Clauses: append([]types.Disjunction{}, clauses...)}
+		count := 0
+		for i, l := range literals {
+			if bits&(1<<i) != 0 {
+				count++
+				sat.Clauses = append(sat.Clauses, types.Disjunction{l})
+			} else {
+				sat.Clauses = append(sat.Clauses, types.Disjunction{l.Negate()})
+			}
+		}
+
+		for k := 1; k <= inputs; k++ {
+			s, err := solver.InitializeBaseSolver(sat, solver.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			solution, _ := s.Solve(totalizer.Outputs[k-1].Negate())
+			if expected := k > count; (solution == types.SATISFIABLE) != expected {
+				t.Errorf("Inputs %05b: expected output %v false to be satisfiable %v, found %v", bits, k, expected, solution)
+			}
+		}
+	}
+}
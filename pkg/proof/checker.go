@@ -0,0 +1,437 @@
+package proof
+
+/*
+The checker file verifies DRAT proofs backwards, only checking the lemmas which the refutation depends on.
+*/
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+// Result of checking a proof
+type Result struct {
+	Verified bool
+	Reason   string              // Why the proof was not verified
+	Core     []types.Disjunction // Clauses of the formula the refutation depends on
+	Lemmas   uint                // No of lemmas of the proof the refutation depends on
+}
+
+// clause of the formula or of the proof
+type clause struct {
+	id       int64
+	literals types.Disjunction
+	lemma    bool    // Added by the proof
+	active   bool    // Part of the formula at the current step of the check
+	core     bool    // The refutation depends on the clause
+	hints    []int64 // LRAT hints found when verifying the lemma
+}
+
+/*
+Checker verifies proofs of unsatisfiability of a formula.
+
+Lemmas are checked by reverse unit propagation (RUP): the negation of the lemma is assigned and unit
+propagation on the active clauses must reach a conflict. Lemmas which are not RUP must have the
+resolution asymmetric tautology (RAT) property on their first literal: every resolvent with an active
+clause containing the negation of the literal must be RUP.
+
+Propagation always starts from the empty assignment, hence the watched literals of a clause can be
+any two of its literals and clauses which are not active are simply skipped.
+*/
+type Checker struct {
+	Clauses []*clause // Clauses in the order they were added, the clauses of the formula have ids 1 to n
+
+	originals uint                 // No of clauses of the formula
+	keys      map[string][]*clause // Active clauses by their sorted literals, used to find deleted clauses
+	watches   [][]*clause          // watches[index(l)] holds the clauses watching literal l
+	units     []*clause            // Clauses with less than 2 literals
+	values    []types.Literal      // values[a] is the literal of atom a in the assignment, 0 if unassigned
+	reasons   []*clause            // reasons[a] is the clause which implied atom a, nil for assumed atoms
+	trail     []types.Literal      // Assigned literals in order
+}
+
+// Constructs a Checker for the clauses of the SATFile
+func ConstructChecker(sat types.SATFile) *Checker {
+	c := &Checker{
+		keys: make(map[string][]*clause),
+	}
+	c.extend(types.Atom(sat.AtomCount))
+
+	for _, d := range sat.Clauses {
+		c.add(d, false)
+	}
+	c.originals = uint(len(c.Clauses))
+
+	return c
+}
+
+// Index of the watch list of a literal
+func index(l types.Literal) int {
+	if l < 0 {
+		return 2*int(l.Atom()) + 1
+	}
+	return 2 * int(l.Atom())
+}
+
+// Key identifying a clause up to the order and repetition of its literals
+func key(d types.Disjunction) string {
+	sorted := append(types.Disjunction{}, d...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	buffer := make([]byte, 0, 8*len(sorted))
+	for i, l := range sorted {
+		if i == 0 || l != sorted[i-1] {
+			buffer = strconv.AppendInt(buffer, int64(l), 10)
+			buffer = append(buffer, ' ')
+		}
+	}
+	return string(buffer)
+}
+
+// Makes room for the atoms up to the given atom
+func (c *Checker) extend(atom types.Atom) {
+	for a := types.Atom(len(c.values)); a <= atom; a++ {
+		c.values = append(c.values, 0)
+		c.reasons = append(c.reasons, nil)
+		c.watches = append(c.watches, nil, nil)
+	}
+}
+
+// Adds an active clause, repeated literals are removed
+func (c *Checker) add(d types.Disjunction, lemma bool) *clause {
+	var literals types.Disjunction
+	seen := make(map[types.Literal]bool)
+	for _, l := range d {
+		if !seen[l] {
+			seen[l] = true
+			literals = append(literals, l)
+			c.extend(l.Atom())
+		}
+	}
+
+	cl := &clause{
+		id:       int64(len(c.Clauses)) + 1,
+		literals: literals,
+		lemma:    lemma,
+		active:   true,
+	}
+	c.Clauses = append(c.Clauses, cl)
+
+	k := key(literals)
+	c.keys[k] = append(c.keys[k], cl)
+
+	if len(literals) < 2 {
+		c.units = append(c.units, cl)
+	} else {
+		c.watches[index(literals[0])] = append(c.watches[index(literals[0])], cl)
+		c.watches[index(literals[1])] = append(c.watches[index(literals[1])], cl)
+	}
+
+	return cl
+}
+
+// Finds the active clause with the given literals and deactivates it, nil if there is none
+func (c *Checker) remove(d types.Disjunction) *clause {
+	k := key(d)
+	matches := c.keys[k]
+	if len(matches) == 0 {
+		return nil
+	}
+
+	cl := matches[len(matches)-1]
+	c.keys[k] = matches[:len(matches)-1]
+	cl.active = false
+	return cl
+}
+
+// Assigns a literal, returns false if its negation is assigned
+func (c *Checker) assign(l types.Literal, reason *clause) bool {
+	switch c.values[l.Atom()] {
+	case l:
+		return true
+	case l.Negate():
+		return false
+	}
+	c.values[l.Atom()] = l
+	c.reasons[l.Atom()] = reason
+	c.trail = append(c.trail, l)
+	return true
+}
+
+// Clears the assignment
+func (c *Checker) reset() {
+	for _, l := range c.trail {
+		c.values[l.Atom()] = 0
+		c.reasons[l.Atom()] = nil
+	}
+	c.trail = c.trail[:0]
+}
+
+/*
+Propagates the active clauses from the current assignment till a clause is refuted or a fixpoint is reached.
+Returns the refuted clause, nil if there is none.
+*/
+func (c *Checker) propagate() *clause {
+	for _, u := range c.units {
+		if !u.active {
+			continue
+		}
+		if len(u.literals) == 0 || !c.assign(u.literals[0], u) {
+			return u
+		}
+	}
+
+	for head := 0; head < len(c.trail); head++ {
+		falsified := c.trail[head].Negate()
+		watchers := c.watches[index(falsified)]
+
+		kept := watchers[:0]
+		var conflict *clause
+		for i, cl := range watchers {
+			if conflict != nil || !cl.active {
+				kept = append(kept, cl)
+				continue
+			}
+
+			lits := cl.literals
+			if lits[0] == falsified {
+				lits[0], lits[1] = lits[1], lits[0]
+			}
+			if c.values[lits[0].Atom()] == lits[0] {
+				kept = append(kept, cl)
+				continue
+			}
+
+			moved := false
+			for k := 2; k < len(lits); k++ {
+				if c.values[lits[k].Atom()] != lits[k].Negate() {
+					lits[1], lits[k] = lits[k], lits[1]
+					c.watches[index(lits[1])] = append(c.watches[index(lits[1])], cl)
+					moved = true
+					break
+				}
+			}
+			if moved {
+				continue
+			}
+
+			kept = append(kept, cl)
+			if !c.assign(lits[0], cl) {
+				conflict = cl
+				kept = append(kept, watchers[i+1:]...)
+				break
+			}
+		}
+		c.watches[index(falsified)] = kept
+
+		if conflict != nil {
+			return conflict
+		}
+	}
+
+	return nil
+}
+
+/*
+Checks if the clause is RUP with the current active clauses. On success the clauses the conflict depends
+on are marked as core, and their ids are returned in the order they became unit, ending with the conflict.
+*/
+func (c *Checker) rup(d types.Disjunction) ([]int64, bool) {
+	defer c.reset()
+
+	for _, l := range d {
+		if !c.assign(l.Negate(), nil) {
+			// The clause is a tautology
+			return nil, true
+		}
+	}
+
+	conflict := c.propagate()
+	if conflict == nil {
+		return nil, false
+	}
+
+	// Walk the implication graph back from the conflict
+	used := map[*clause]bool{conflict: true}
+	stack := []*clause{conflict}
+	for len(stack) > 0 {
+		cl := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		cl.core = true
+		for _, l := range cl.literals {
+			if r := c.reasons[l.Atom()]; r != nil && !used[r] {
+				used[r] = true
+				stack = append(stack, r)
+			}
+		}
+	}
+
+	var hints []int64
+	for _, l := range c.trail {
+		if r := c.reasons[l.Atom()]; r != nil && r != conflict && used[r] {
+			hints = append(hints, r.id)
+			used[r] = false
+		}
+	}
+	return append(hints, conflict.id), true
+}
+
+/*
+Checks if the lemma is RUP, otherwise if it is RAT on its first literal. Returns the LRAT hints of the
+lemma, a RAT candidate is given by its negated id followed by the hints of its resolvent.
+*/
+func (c *Checker) verify(lemma *clause) ([]int64, bool) {
+	if hints, ok := c.rup(lemma.literals); ok {
+		return hints, true
+	}
+	if len(lemma.literals) == 0 {
+		return nil, false
+	}
+
+	pivot := lemma.literals[0]
+	var hints []int64
+	for _, cl := range c.Clauses[:lemma.id-1] {
+		if !cl.active || !containsLiteral(cl.literals, pivot.Negate()) {
+			continue
+		}
+
+		resolvent := append(types.Disjunction{}, lemma.literals...)
+		for _, l := range cl.literals {
+			if l != pivot.Negate() {
+				resolvent = append(resolvent, l)
+			}
+		}
+
+		candidate, ok := c.rup(resolvent)
+		if !ok {
+			return nil, false
+		}
+		cl.core = true
+		hints = append(hints, -cl.id)
+		hints = append(hints, candidate...)
+	}
+
+	return hints, true
+}
+
+func containsLiteral(d types.Disjunction, lit types.Literal) bool {
+	for _, l := range d {
+		if l == lit {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+CheckDRAT verifies a DRAT proof of unsatisfiability.
+
+The proof is first replayed forwards till the empty clause is added. If the proof has no empty clause,
+the clauses left at its end must be refuted by unit propagation. The lemmas are then checked backwards,
+skipping those the refutation does not depend on. Deletions of unit clauses are ignored.
+*/
+func (c *Checker) CheckDRAT(steps []Step) Result {
+	type event struct {
+		clause *clause
+		delete bool
+	}
+	var events []event
+
+	var final *clause
+	for _, step := range steps {
+		if step.Delete {
+			if len(step.Clause) < 2 {
+				continue
+			}
+			if cl := c.remove(step.Clause); cl != nil {
+				events = append(events, event{clause: cl, delete: true})
+			}
+			continue
+		}
+
+		cl := c.add(step.Clause, true)
+		events = append(events, event{clause: cl})
+		if len(cl.literals) == 0 {
+			final = cl
+			break
+		}
+	}
+
+	if final == nil {
+		final = c.add(nil, true)
+		events = append(events, event{clause: final})
+	}
+	final.core = true
+
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		if e.delete {
+			e.clause.active = true
+			continue
+		}
+
+		e.clause.active = false
+		if !e.clause.core {
+			continue
+		}
+
+		hints, ok := c.verify(e.clause)
+		if !ok {
+			return Result{Reason: fmt.Sprintf("Lemma %v could not be verified", e.clause.literals)}
+		}
+		e.clause.hints = hints
+	}
+
+	return c.result()
+}
+
+// Builds the Result of a verified proof from the core clauses
+func (c *Checker) result() Result {
+	result := Result{Verified: true}
+	for _, cl := range c.Clauses {
+		if !cl.core {
+			continue
+		}
+		if cl.lemma {
+			result.Lemmas++
+		} else {
+			result.Core = append(result.Core, cl.literals)
+		}
+	}
+	return result
+}
+
+/*
+WriteLRAT writes the lemmas of a verified DRAT proof which the refutation depends on as an LRAT proof.
+The clauses of the formula keep their position in the input as their id.
+*/
+func (c *Checker) WriteLRAT(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	for _, cl := range c.Clauses {
+		if !cl.lemma || !cl.core {
+			continue
+		}
+
+		line := strconv.AppendInt(nil, cl.id, 10)
+		for _, l := range cl.literals {
+			line = append(line, ' ')
+			line = strconv.AppendInt(line, int64(l), 10)
+		}
+		line = append(line, " 0"...)
+		for _, h := range cl.hints {
+			line = append(line, ' ')
+			line = strconv.AppendInt(line, h, 10)
+		}
+		line = append(line, " 0\n"...)
+
+		if _, err := out.Write(line); err != nil {
+			return err
+		}
+	}
+	return out.Flush()
+}
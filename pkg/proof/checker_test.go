@@ -0,0 +1,89 @@
+package proof_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	proof "github.com/alanpjohn/go-cdcl/pkg/proof"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+// Every assignment of atoms 1 and 2 is refuted, the last clause is not needed
+var sample = types.SATFile{
+	AtomCount: 3,
+	Clauses: []types.Disjunction{
+		{1, 2},
+		{1, -2},
+		{-1, 2},
+		{-1, -2},
+		{1, 2, 3},
+	},
+}
+
+func check(t *testing.T, drat string) (*proof.Checker, proof.Result) {
+	steps, err := proof.ParseDRAT(strings.NewReader(drat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker := proof.ConstructChecker(sample)
+	return checker, checker.CheckDRAT(steps)
+}
+
+func TestCheckDRAT(t *testing.T) {
+	checker, result := check(t, "c comment\n3 -2 0\n1 0\nd 1 2 3 0\n0\n")
+	if !result.Verified {
+		t.Fatalf("Expected proof to be verified: %v", result.Reason)
+	}
+	// The first lemma is not needed by the refutation
+	if result.Lemmas != 2 || len(result.Core) != 4 {
+		t.Errorf("Expected 2 lemmas and 4 core clauses, found %v and %v", result.Lemmas, result.Core)
+	}
+
+	var lrat bytes.Buffer
+	if err := checker.WriteLRAT(&lrat); err != nil {
+		t.Fatal(err)
+	}
+	steps, err := proof.ParseLRAT(&lrat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result = proof.ConstructChecker(sample).CheckLRAT(steps); !result.Verified {
+		t.Errorf("Expected LRAT output to be verified: %v", result.Reason)
+	}
+}
+
+func TestCheckDRATFailure(t *testing.T) {
+	// Without the empty clause the remaining clauses have to be refuted by unit propagation
+	if _, result := check(t, "3 0\n"); result.Verified {
+		t.Errorf("Expected proof without refutation to be rejected")
+	}
+
+	// A lemma needed by the refutation which is neither RUP nor RAT
+	satisfiable := types.SATFile{AtomCount: 3, Clauses: []types.Disjunction{{-3, 1}, {-3, -1}}}
+	steps, _ := proof.ParseDRAT(strings.NewReader("3 0\n0\n"))
+	if result := proof.ConstructChecker(satisfiable).CheckDRAT(steps); result.Verified {
+		t.Errorf("Expected lemma 3 to be rejected")
+	}
+}
+
+func TestCheckLRATRAT(t *testing.T) {
+	// Clause 6 introduces the fresh atom 4, clause 7 is RAT on -4 using clause 6
+	lrat := "6 4 0 0\n7 -4 1 0 -6 1 2 0\n8 1 0 1 2 0\n8 d 5 0\n9 0 8 3 4 0\n"
+	steps, err := proof.ParseLRAT(strings.NewReader(lrat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := proof.ConstructChecker(sample).CheckLRAT(steps)
+	if !result.Verified {
+		t.Fatalf("Expected proof to be verified: %v", result.Reason)
+	}
+	if result.Lemmas != 2 || len(result.Core) != 4 {
+		t.Errorf("Expected 2 lemmas and 4 core clauses, found %v and %v", result.Lemmas, result.Core)
+	}
+
+	steps, _ = proof.ParseLRAT(strings.NewReader("6 1 0 1 0\n7 0 6 3 4 0\n"))
+	if result = proof.ConstructChecker(sample).CheckLRAT(steps); result.Verified {
+		t.Errorf("Expected lemma with insufficient hints to be rejected")
+	}
+}
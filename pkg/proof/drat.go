@@ -0,0 +1,91 @@
+/*
+The proof package records and checks the proofs of unsatisfiability produced by the solver
+*/
+package proof
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+DRATWriter writes a DRAT proof, the clauses added to and deleted from the formula in order.
+
+In the text encoding a clause is written as its literals terminated by 0, deletions are prefixed by `d`.
+In the binary encoding a clause starts with the byte `a` or `d` and every literal l is written as the
+unsigned integer 2*|l| + (1 if l is negative) in 7 bit groups, the last group first, terminated by 0.
+*/
+type DRATWriter struct {
+	Binary bool // Use the binary encoding
+	out    *bufio.Writer
+	err    error // First error returned by the underlying writer
+	buffer []byte
+}
+
+// Constructs a DRATWriter writing to w
+func ConstructDRATWriter(w io.Writer, binary bool) *DRATWriter {
+	return &DRATWriter{
+		Binary: binary,
+		out:    bufio.NewWriter(w),
+	}
+}
+
+// Records a clause added to the formula
+func (p *DRATWriter) Add(d types.Disjunction) {
+	p.write('a', d)
+}
+
+// Records a clause deleted from the formula
+func (p *DRATWriter) Delete(d types.Disjunction) {
+	p.write('d', d)
+}
+
+func (p *DRATWriter) write(kind byte, d types.Disjunction) {
+	if p.err != nil {
+		return
+	}
+
+	buffer := p.buffer[:0]
+	if p.Binary {
+		buffer = append(buffer, kind)
+		for _, l := range d {
+			u := 2 * uint64(l.Atom())
+			if l < 0 {
+				u++
+			}
+			for u > 127 {
+				buffer = append(buffer, byte(u&127)|128)
+				u >>= 7
+			}
+			buffer = append(buffer, byte(u))
+		}
+		buffer = append(buffer, 0)
+	} else {
+		if kind == 'd' {
+			buffer = append(buffer, "d "...)
+		}
+		for _, l := range d {
+			buffer = strconv.AppendInt(buffer, int64(l), 10)
+			buffer = append(buffer, ' ')
+		}
+		buffer = append(buffer, "0\n"...)
+	}
+	p.buffer = buffer
+
+	_, p.err = p.out.Write(buffer)
+}
+
+// Writes the buffered part of the proof, returns the first error met while writing the proof
+func (p *DRATWriter) Flush() error {
+	if p.err == nil {
+		p.err = p.out.Flush()
+	}
+	if p.err != nil {
+		return handler.Throw("Proof could not be written", p.err)
+	}
+	return nil
+}
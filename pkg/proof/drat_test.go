@@ -0,0 +1,40 @@
+package proof_test
+
+import (
+	"bytes"
+	"testing"
+
+	proof "github.com/alanpjohn/go-cdcl/pkg/proof"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestDRATText(t *testing.T) {
+	var out bytes.Buffer
+	p := proof.ConstructDRATWriter(&out, false)
+	p.Add(types.Disjunction{1, -2})
+	p.Delete(types.Disjunction{1, -2})
+	p.Add(types.Disjunction{})
+	if err := p.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.String() != "1 -2 0\nd 1 -2 0\n0\n" {
+		t.Errorf("Unexpected proof %q", out.String())
+	}
+}
+
+func TestDRATBinary(t *testing.T) {
+	var out bytes.Buffer
+	p := proof.ConstructDRATWriter(&out, true)
+	p.Add(types.Disjunction{1, -63, 64})
+	p.Delete(types.Disjunction{-1})
+	if err := p.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 1 -> 2, -63 -> 127, 64 -> 128 which takes two bytes
+	expected := []byte{'a', 2, 127, 0x80, 0x01, 0, 'd', 3, 0}
+	if !bytes.Equal(out.Bytes(), expected) {
+		t.Errorf("Expected %v, found %v", expected, out.Bytes())
+	}
+}
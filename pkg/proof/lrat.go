@@ -0,0 +1,205 @@
+package proof
+
+/*
+The lrat file verifies LRAT proofs forwards, where every lemma lists the clauses needed to verify it.
+*/
+
+import (
+	"fmt"
+
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+// Outcome of asserting a hint during the check of an LRAT lemma
+type HintState uint
+
+const (
+	HINT_UNIT     HintState = iota // The hint implied its only unassigned literal
+	HINT_CONFLICT                  // Every literal of the hint is false
+	HINT_INVALID                   // The hint is missing, satisfied or has several unassigned literals
+)
+
+// Asserts a hint clause under the current assignment
+func (c *Checker) hint(cl *clause) HintState {
+	if cl == nil {
+		return HINT_INVALID
+	}
+
+	var unit types.Literal
+	for _, l := range cl.literals {
+		switch c.values[l.Atom()] {
+		case l:
+			return HINT_INVALID
+		case 0:
+			if unit != 0 {
+				return HINT_INVALID
+			}
+			unit = l
+		}
+	}
+
+	if unit == 0 {
+		return HINT_CONFLICT
+	}
+	c.assign(unit, cl)
+	return HINT_UNIT
+}
+
+// Unassigns every literal after the given position of the trail
+func (c *Checker) backtrack(size int) {
+	for _, l := range c.trail[size:] {
+		c.values[l.Atom()] = 0
+		c.reasons[l.Atom()] = nil
+	}
+	c.trail = c.trail[:size]
+}
+
+// Asserts hints till one of them is refuted, returns false if no hint is refuted
+func (c *Checker) refute(hints []int64, clauses map[int64]*clause) bool {
+	for _, h := range hints {
+		switch c.hint(clauses[h]) {
+		case HINT_CONFLICT:
+			return true
+		case HINT_INVALID:
+			return false
+		}
+	}
+	return false
+}
+
+/*
+Checks a lemma with its hints. The positive hints before the first negative hint must refute the
+negation of the lemma. Otherwise every active clause containing the negation of the first literal
+of the lemma must have a group of hints, started by its negated id, refuting the resolvent.
+*/
+func (c *Checker) checkHints(literals types.Disjunction, hints []int64, clauses map[int64]*clause) bool {
+	defer c.reset()
+
+	for _, l := range literals {
+		if !c.assign(l.Negate(), nil) {
+			// The clause is a tautology
+			return true
+		}
+	}
+
+	i := 0
+	for i < len(hints) && hints[i] > 0 {
+		i++
+	}
+	if c.refute(hints[:i], clauses) {
+		return true
+	}
+	if len(literals) == 0 {
+		return false
+	}
+
+	groups := make(map[int64][]int64)
+	for i < len(hints) {
+		start := i
+		for i++; i < len(hints) && hints[i] > 0; i++ {
+		}
+		groups[-hints[start]] = hints[start+1 : i]
+	}
+
+	pivot := literals[0]
+	for id, cl := range clauses {
+		if !cl.active || !containsLiteral(cl.literals, pivot.Negate()) {
+			continue
+		}
+
+		size := len(c.trail)
+		satisfied := false
+		for _, l := range cl.literals {
+			if l != pivot.Negate() && !c.assign(l.Negate(), nil) {
+				satisfied = true
+				break
+			}
+		}
+
+		group, ok := groups[id]
+		if !satisfied && (!ok || !c.refute(group, clauses)) {
+			return false
+		}
+		c.backtrack(size)
+	}
+
+	return true
+}
+
+/*
+CheckLRAT verifies an LRAT proof of unsatisfiability.
+
+Every lemma is checked with its hints as it is added, and the proof must add the empty clause.
+The core is found by following the hints back from the empty clause.
+*/
+func (c *Checker) CheckLRAT(steps []LRATStep) Result {
+	clauses := make(map[int64]*clause)
+	for _, cl := range c.Clauses {
+		clauses[cl.id] = cl
+	}
+
+	var final *clause
+	for _, step := range steps {
+		if step.Delete {
+			for _, id := range step.Hints {
+				if cl, ok := clauses[id]; ok {
+					cl.active = false
+					delete(clauses, id)
+				}
+			}
+			continue
+		}
+
+		if _, ok := clauses[step.ID]; ok || step.ID <= int64(c.originals) {
+			return Result{Reason: fmt.Sprintf("Clause id %v is used twice", step.ID)}
+		}
+		for _, l := range step.Clause {
+			c.extend(l.Atom())
+		}
+		if !c.checkHints(step.Clause, step.Hints, clauses) {
+			return Result{Reason: fmt.Sprintf("Lemma %v %v could not be verified", step.ID, step.Clause)}
+		}
+
+		cl := &clause{
+			id:       step.ID,
+			literals: step.Clause,
+			lemma:    true,
+			active:   true,
+			hints:    step.Hints,
+		}
+		c.Clauses = append(c.Clauses, cl)
+		clauses[cl.id] = cl
+
+		if len(step.Clause) == 0 {
+			final = cl
+			break
+		}
+	}
+
+	if final == nil {
+		return Result{Reason: "The proof does not add the empty clause"}
+	}
+
+	// Mark the clauses the empty clause depends on, deleted clauses are still reachable by id
+	byID := make(map[int64]*clause)
+	for _, cl := range c.Clauses {
+		byID[cl.id] = cl
+	}
+	stack := []*clause{final}
+	final.core = true
+	for len(stack) > 0 {
+		cl := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, h := range cl.hints {
+			if h < 0 {
+				h = -h
+			}
+			if dep := byID[h]; dep != nil && !dep.core {
+				dep.core = true
+				stack = append(stack, dep)
+			}
+		}
+	}
+
+	return c.result()
+}
@@ -0,0 +1,199 @@
+package proof
+
+/*
+The parse file reads DRAT proofs in the text or binary encoding and LRAT proofs in the text encoding.
+*/
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+// Step is a clause added to or deleted from the formula by a DRAT proof
+type Step struct {
+	Delete bool
+	Clause types.Disjunction
+}
+
+/*
+LRATStep is a line of an LRAT proof.
+
+An addition has the id of the new clause, its literals and the ids of the clauses which become unit
+one after the other till the clause is refuted. A negative hint starts the hints of a RAT candidate.
+A deletion lists the ids of the deleted clauses in Hints.
+*/
+type LRATStep struct {
+	ID     int64
+	Delete bool
+	Clause types.Disjunction
+	Hints  []int64
+}
+
+/*
+ParseDRAT reads a DRAT proof. The binary encoding is detected from the first bytes of the proof,
+as text proofs only contain printable characters.
+*/
+func ParseDRAT(r io.Reader) ([]Step, error) {
+	in := bufio.NewReader(r)
+	head, _ := in.Peek(16)
+	for _, b := range head {
+		if b != '\n' && b != '\r' && b != '\t' && (b < 32 || b > 126) {
+			return parseBinaryDRAT(in)
+		}
+	}
+	return parseTextDRAT(in)
+}
+
+// Clauses may span several lines, lines starting with `c` are comments
+func parseTextDRAT(in *bufio.Reader) ([]Step, error) {
+	var (
+		steps []Step
+		step  Step
+	)
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 1<<16), 1<<30)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 && fields[0] == "c" {
+			continue
+		}
+
+		for _, token := range fields {
+			if token == "d" {
+				step.Delete = true
+				continue
+			}
+			lit, err := strconv.ParseInt(token, 10, 64)
+			if err != nil {
+				return nil, handler.Throw(fmt.Sprintf("Invalid literal %q in DRAT proof", token), err)
+			}
+			if lit == 0 {
+				steps = append(steps, step)
+				step = Step{}
+			} else {
+				step.Clause = append(step.Clause, types.Literal(lit))
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, handler.Throw("DRAT proof could not be read", err)
+	}
+	if step.Delete || len(step.Clause) > 0 {
+		return nil, handler.Throw("DRAT proof ends in the middle of a clause", nil)
+	}
+
+	return steps, nil
+}
+
+func parseBinaryDRAT(in *bufio.Reader) ([]Step, error) {
+	var steps []Step
+
+	for {
+		kind, err := in.ReadByte()
+		if err == io.EOF {
+			return steps, nil
+		} else if err != nil {
+			return nil, handler.Throw("DRAT proof could not be read", err)
+		}
+		if kind != 'a' && kind != 'd' {
+			return nil, handler.Throw(fmt.Sprintf("Invalid step %q in binary DRAT proof", kind), nil)
+		}
+
+		step := Step{Delete: kind == 'd'}
+		for {
+			var u uint64
+			for shift := 0; ; shift += 7 {
+				b, err := in.ReadByte()
+				if err != nil {
+					return nil, handler.Throw("Binary DRAT proof ends in the middle of a clause", err)
+				}
+				u |= uint64(b&127) << shift
+				if b < 128 {
+					break
+				}
+			}
+			if u == 0 {
+				break
+			}
+			lit := types.Literal(u >> 1)
+			if u&1 == 1 {
+				lit = lit.Negate()
+			}
+			step.Clause = append(step.Clause, lit)
+		}
+		steps = append(steps, step)
+	}
+}
+
+// ParseLRAT reads an LRAT proof in the text encoding
+func ParseLRAT(r io.Reader) ([]LRATStep, error) {
+	var steps []LRATStep
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 1<<16), 1<<30)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] == "c" {
+			continue
+		}
+
+		numbers := make([]int64, 0, len(fields))
+		step := LRATStep{}
+		for i, f := range fields {
+			if i == 1 && f == "d" {
+				step.Delete = true
+				continue
+			}
+			n, err := strconv.ParseInt(f, 10, 64)
+			if err != nil {
+				return nil, handler.Throw(fmt.Sprintf("Invalid number %q on line %v of LRAT proof", f, line), err)
+			}
+			numbers = append(numbers, n)
+		}
+
+		step.ID = numbers[0]
+		rest := numbers[1:]
+		if !step.Delete {
+			end := indexOfZero(rest)
+			if end < 0 {
+				return nil, handler.Throw(fmt.Sprintf("Clause is not terminated by 0 on line %v of LRAT proof", line), nil)
+			}
+			for _, l := range rest[:end] {
+				step.Clause = append(step.Clause, types.Literal(l))
+			}
+			rest = rest[end+1:]
+		}
+
+		end := indexOfZero(rest)
+		if end < 0 {
+			return nil, handler.Throw(fmt.Sprintf("Hints are not terminated by 0 on line %v of LRAT proof", line), nil)
+		}
+		step.Hints = rest[:end]
+		steps = append(steps, step)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, handler.Throw("LRAT proof could not be read", err)
+	}
+
+	return steps, nil
+}
+
+func indexOfZero(numbers []int64) int {
+	for i, n := range numbers {
+		if n == 0 {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,292 @@
+package solver
+
+/*
+The brancher file contains the decision heuristics used by the solver to select the next atom to decide on.
+*/
+
+import (
+	"container/heap"
+	"math/rand"
+	"sort"
+
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+Brancher selects the atom the solver decides on next.
+
+The solver informs the Brancher of the atoms seen during conflict analysis and of the atoms
+removed from the Trail on backjumping, so that the Brancher can keep track of its candidates.
+*/
+type Brancher interface {
+	Select(trail *Trail) types.Atom // Returns an unassigned atom, 0 if every atom is assigned
+	Bump(a types.Atom)              // Called for every atom seen during conflict analysis
+	Decay()                         // Called once per conflict after the atoms have been bumped
+	Unassign(a types.Atom)          // Called for every atom removed from the Trail
+	Extend(atomCount uint)          // Called when atoms are added to the Formula
+}
+
+/*
+BrancherType is an enum defining the implementations of the Brancher interface
+that the solver can be initialized with.
+*/
+type BrancherType uint
+
+const (
+	VSIDS_BRANCHER  BrancherType = iota // Exponential variable state independent decaying sum
+	VMTF_BRANCHER                       // Variable move to front
+	RANDOM_BRANCHER                     // Uniformly random selection
+)
+
+/*
+VSIDS implements the Brancher interface using exponential VSIDS.
+
+Every bump adds an increment to the activity of an atom, and the increment grows after every
+conflict which decays the activity of all atoms that are not bumped. Unassigned atoms are kept
+in a binary heap ordered by activity.
+*/
+type VSIDS struct {
+	Activity  []float64 // Activity of each atom
+	increment float64   // Value added to the activity of a bumped atom
+	decay     float64   // Factor by which the activity of atoms decays after every conflict
+	order     activityHeap
+}
+
+// Constructs VSIDS for the given number of atoms with every atom in the heap
+func ConstructVSIDS(atomCount uint, decay float64) *VSIDS {
+	v := &VSIDS{
+		Activity:  make([]float64, atomCount+1),
+		increment: 1,
+		decay:     decay,
+	}
+	v.order = activityHeap{
+		activity: v.Activity,
+		index:    make([]int, atomCount+1),
+	}
+	v.order.index[0] = -1
+	for a := types.Atom(1); a <= types.Atom(atomCount); a++ {
+		v.order.atoms = append(v.order.atoms, a)
+		v.order.index[a] = int(a) - 1
+	}
+	return v
+}
+
+// Pops atoms from the heap till an unassigned atom is found
+func (v *VSIDS) Select(trail *Trail) types.Atom {
+	for v.order.Len() > 0 {
+		a := heap.Pop(&v.order).(types.Atom)
+		if !trail.Assigned(a) {
+			return a
+		}
+	}
+	return 0
+}
+
+func (v *VSIDS) Bump(a types.Atom) {
+	v.Activity[a] += v.increment
+
+	// Rescale all activities before they overflow
+	if v.Activity[a] > 1e100 {
+		for i := range v.Activity {
+			v.Activity[i] *= 1e-100
+		}
+		v.increment *= 1e-100
+	}
+
+	if i := v.order.index[a]; i >= 0 {
+		heap.Fix(&v.order, i)
+	}
+}
+
+func (v *VSIDS) Decay() {
+	v.increment /= v.decay
+}
+
+func (v *VSIDS) Unassign(a types.Atom) {
+	if v.order.index[a] < 0 {
+		heap.Push(&v.order, a)
+	}
+}
+
+// New atoms start with no activity
+func (v *VSIDS) Extend(atomCount uint) {
+	for a := types.Atom(len(v.Activity)); a <= types.Atom(atomCount); a++ {
+		v.Activity = append(v.Activity, 0)
+		v.order.index = append(v.order.index, -1)
+		v.order.activity = v.Activity
+		heap.Push(&v.order, a)
+	}
+}
+
+// activityHeap is a max heap of atoms ordered by activity
+type activityHeap struct {
+	atoms    []types.Atom
+	index    []int // index[a] is the position of atom a in atoms, -1 if it is not in the heap
+	activity []float64
+}
+
+func (h activityHeap) Len() int { return len(h.atoms) }
+
+func (h activityHeap) Less(i, j int) bool {
+	return h.activity[h.atoms[i]] > h.activity[h.atoms[j]]
+}
+
+func (h activityHeap) Swap(i, j int) {
+	h.atoms[i], h.atoms[j] = h.atoms[j], h.atoms[i]
+	h.index[h.atoms[i]] = i
+	h.index[h.atoms[j]] = j
+}
+
+func (h *activityHeap) Push(x any) {
+	a := x.(types.Atom)
+	h.index[a] = len(h.atoms)
+	h.atoms = append(h.atoms, a)
+}
+
+func (h *activityHeap) Pop() any {
+	n := len(h.atoms)
+	a := h.atoms[n-1]
+	h.index[a] = -1
+	h.atoms = h.atoms[:n-1]
+	return a
+}
+
+/*
+VMTF implements the Brancher interface using the variable move to front heuristic.
+
+Atoms are kept in a doubly linked queue where bumped atoms are moved to the front. Every atom
+carries the time it was last moved, and the search starts from the most recently moved atom
+that might still be unassigned.
+*/
+type VMTF struct {
+	prev   []types.Atom // prev[a] is the atom moved before a, 0 at the back of the queue
+	next   []types.Atom // next[a] is the atom moved after a, 0 at the front of the queue
+	stamps []uint       // stamps[a] is the time atom a was last moved to the front
+	front  types.Atom   // Most recently moved atom
+	search types.Atom   // Every atom moved after search is assigned
+	bumped []types.Atom // Atoms bumped during the current conflict
+	clock  uint
+}
+
+// Constructs VMTF for the given number of atoms with atom 1 at the back of the queue
+func ConstructVMTF(atomCount uint) *VMTF {
+	v := &VMTF{
+		prev:   make([]types.Atom, atomCount+1),
+		next:   make([]types.Atom, atomCount+1),
+		stamps: make([]uint, atomCount+1),
+	}
+	for a := types.Atom(1); a <= types.Atom(atomCount); a++ {
+		v.enqueue(a)
+	}
+	v.search = v.front
+	return v
+}
+
+// Appends the atom to the front of the queue
+func (v *VMTF) enqueue(a types.Atom) {
+	v.clock++
+	v.stamps[a] = v.clock
+	v.prev[a] = v.front
+	v.next[a] = 0
+	if v.front != 0 {
+		v.next[v.front] = a
+	}
+	v.front = a
+}
+
+// Walks the queue from the search atom towards the back till an unassigned atom is found
+func (v *VMTF) Select(trail *Trail) types.Atom {
+	for a := v.search; a != 0; a = v.prev[a] {
+		if !trail.Assigned(a) {
+			v.search = a
+			return a
+		}
+	}
+	return 0
+}
+
+// Bumped atoms are moved to the front once the conflict has been analysed
+func (v *VMTF) Bump(a types.Atom) {
+	v.bumped = append(v.bumped, a)
+}
+
+/*
+Moves the atoms bumped during the conflict to the front of the queue.
+The atoms are moved in the order of their stamps so that their relative order is preserved.
+*/
+func (v *VMTF) Decay() {
+	sort.Slice(v.bumped, func(i, j int) bool {
+		return v.stamps[v.bumped[i]] < v.stamps[v.bumped[j]]
+	})
+
+	for _, a := range v.bumped {
+		if a != v.front {
+			// Unlink the atom before moving it to the front
+			if v.prev[a] != 0 {
+				v.next[v.prev[a]] = v.next[a]
+			}
+			v.prev[v.next[a]] = v.prev[a]
+			v.enqueue(a)
+		}
+		v.search = a
+	}
+	v.bumped = v.bumped[:0]
+}
+
+func (v *VMTF) Unassign(a types.Atom) {
+	if v.search == 0 || v.stamps[a] > v.stamps[v.search] {
+		v.search = a
+	}
+}
+
+// New atoms are moved to the front of the queue
+func (v *VMTF) Extend(atomCount uint) {
+	for a := types.Atom(len(v.stamps)); a <= types.Atom(atomCount); a++ {
+		v.prev = append(v.prev, 0)
+		v.next = append(v.next, 0)
+		v.stamps = append(v.stamps, 0)
+		v.enqueue(a)
+		v.search = a
+	}
+}
+
+// RandomBrancher implements the Brancher interface by selecting unassigned atoms uniformly at random
+type RandomBrancher struct {
+	AtomCount uint
+	random    *rand.Rand
+}
+
+// Constructs a RandomBrancher seeded with the given seed
+func ConstructRandomBrancher(atomCount uint, seed int64) *RandomBrancher {
+	return &RandomBrancher{
+		AtomCount: atomCount,
+		random:    rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Picks a random atom and walks forward from it till an unassigned atom is found
+func (r *RandomBrancher) Select(trail *Trail) types.Atom {
+	if r.AtomCount == 0 {
+		return 0
+	}
+	start := uint(r.random.Intn(int(r.AtomCount)))
+	for i := uint(0); i < r.AtomCount; i++ {
+		a := types.Atom((start+i)%r.AtomCount + 1)
+		if !trail.Assigned(a) {
+			return a
+		}
+	}
+	return 0
+}
+
+func (r *RandomBrancher) Bump(a types.Atom) {}
+
+func (r *RandomBrancher) Decay() {}
+
+func (r *RandomBrancher) Unassign(a types.Atom) {}
+
+func (r *RandomBrancher) Extend(atomCount uint) {
+	if atomCount > r.AtomCount {
+		r.AtomCount = atomCount
+	}
+}
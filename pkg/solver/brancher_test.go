@@ -0,0 +1,82 @@
+package solver_test
+
+import (
+	"testing"
+
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestVSIDS(t *testing.T) {
+	trail := solver.ConstructTrail(5)
+	vsids := solver.ConstructVSIDS(5, 0.5)
+
+	vsids.Bump(2)
+	vsids.Decay()
+	vsids.Bump(4)
+	vsids.Bump(4)
+	vsids.Decay()
+
+	if a := vsids.Select(&trail); a != 4 {
+		t.Errorf("Expected atom 4 to be selected, found %v", a)
+	}
+
+	trail.Pushback(solver.ModelElement{Literal: 4, Decision: true})
+	if a := vsids.Select(&trail); a != 2 {
+		t.Errorf("Expected atom 2 to be selected, found %v", a)
+	}
+
+	trail.Pushback(solver.ModelElement{Literal: -2, Decision: true})
+	for _, l := range trail.Backjump(0) {
+		vsids.Unassign(l.Atom())
+	}
+	if a := vsids.Select(&trail); a != 4 {
+		t.Errorf("Expected atom 4 to be selected after backjump, found %v", a)
+	}
+}
+
+func TestVMTF(t *testing.T) {
+	trail := solver.ConstructTrail(5)
+	vmtf := solver.ConstructVMTF(5)
+
+	if a := vmtf.Select(&trail); a != 5 {
+		t.Errorf("Expected atom 5 to be selected, found %v", a)
+	}
+
+	vmtf.Bump(3)
+	vmtf.Bump(1)
+	vmtf.Decay()
+
+	if a := vmtf.Select(&trail); a != 3 {
+		t.Errorf("Expected atom 3 to be selected, found %v", a)
+	}
+
+	trail.Pushback(solver.ModelElement{Literal: 3, Decision: true})
+	if a := vmtf.Select(&trail); a != 1 {
+		t.Errorf("Expected atom 1 to be selected, found %v", a)
+	}
+
+	for _, l := range trail.Backjump(0) {
+		vmtf.Unassign(l.Atom())
+	}
+	if a := vmtf.Select(&trail); a != 3 {
+		t.Errorf("Expected atom 3 to be selected after backjump, found %v", a)
+	}
+}
+
+func TestRandomBrancher(t *testing.T) {
+	trail := solver.ConstructTrail(3)
+	random := solver.ConstructRandomBrancher(3, 1)
+
+	for i := 0; i < 3; i++ {
+		a := random.Select(&trail)
+		if a == 0 || trail.Assigned(a) {
+			t.Fatalf("Expected an unassigned atom, found %v", a)
+		}
+		trail.Pushback(solver.ModelElement{Literal: types.Literal(a), Decision: true})
+	}
+
+	if a := random.Select(&trail); a != 0 {
+		t.Errorf("Expected no atom to be selected, found %v", a)
+	}
+}
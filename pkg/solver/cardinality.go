@@ -0,0 +1,341 @@
+package solver
+
+/*
+The cardinality file propagates cardinality constraints natively instead of encoding them into clauses.
+
+A constraint asks for at least k of its n literals to be true, and counts its refuted literals. Once n-k
+literals are refuted every unassigned literal is implied, and a further refuted literal is a conflict.
+At most k of a set of literals is at least n-k of their negations.
+
+Reasons are built lazily: a literal implied by the constraint gets a CardinalityReason, whose clause is
+only built if conflict analysis asks for it. The clause takes n-k literals refuted before the implied literal,
+which are found from the order in which the formula assigned them.
+*/
+
+import (
+	"fmt"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+CardinalityClause implements the Constraint interface for the constraint that at least Bound of its
+literals are true. Like the WatchedClause, the assignment is read from the formula.
+*/
+type CardinalityClause struct {
+	literals types.Disjunction // Distinct literals of the constraint
+	bound    int               // No of literals which must be true
+	refuted  int               // No of refuted literals
+	formula  *WatchedFormula
+}
+
+// Constructs a CardinalityClause from a Cardinality, repeated literals are removed
+func ConstructCardinalityClause(c types.Cardinality) *CardinalityClause {
+	return &CardinalityClause{
+		literals: normalize(c.Literals),
+		bound:    int(c.Bound),
+	}
+}
+
+// Returns the no of refuted literals which leaves every other literal implied
+func (c *CardinalityClause) limit() int {
+	return len(c.literals) - c.bound
+}
+
+// Counts the true and the refuted literals in the current assignment
+func (c *CardinalityClause) counts() (assigned int, refuted int) {
+	for _, l := range c.literals {
+		switch c.formula.state(l) {
+		case ASSIGNED:
+			assigned++
+		case REFUTED:
+			refuted++
+		}
+	}
+	return assigned, refuted
+}
+
+func (c *CardinalityClause) Type() types.ClauseType {
+	assigned, refuted := c.counts()
+	switch {
+	case assigned >= c.bound:
+		return types.SOLVED_CLAUSE
+	case refuted > c.limit():
+		return types.EMPTY_CLAUSE
+	case refuted == c.limit():
+		return types.UNIT_CLAUSE
+	}
+	return types.DECISION_CLAUSE
+}
+
+// Assignments are tracked by the WatchedFormula, hence Apply does not change the constraint
+func (c *CardinalityClause) Apply(l types.Literal) types.Clause {
+	return c
+}
+
+// Assignments are tracked by the WatchedFormula, hence Undo does not change the constraint
+func (c *CardinalityClause) Undo(l types.Literal) types.Clause {
+	return c
+}
+
+// Recounts the refuted literals in the current assignment of the formula
+func (c *CardinalityClause) Reset() types.Clause {
+	_, c.refuted = c.counts()
+	return c
+}
+
+func (c *CardinalityClause) Contains(l types.Literal) bool {
+	for _, cl := range c.literals {
+		if cl == l {
+			return c.formula.state(l) != REFUTED
+		}
+	}
+	return false
+}
+
+func (c *CardinalityClause) IsSolved() bool {
+	assigned, _ := c.counts()
+	return assigned >= c.bound
+}
+
+func (c *CardinalityClause) IsLearnt() bool {
+	return false
+}
+
+// Returns the literals of the constraint which are not refuted, the unassigned literals first
+func (c *CardinalityClause) Disjunction() types.Disjunction {
+	var unassigned, assigned types.Disjunction
+	for _, l := range c.literals {
+		switch c.formula.state(l) {
+		case UNASSIGNED:
+			unassigned = append(unassigned, l)
+		case ASSIGNED:
+			assigned = append(assigned, l)
+		}
+	}
+	return append(unassigned, assigned...)
+}
+
+func (c *CardinalityClause) Original() types.Disjunction {
+	return c.literals
+}
+
+// Returns the constraint as a Cardinality
+func (c *CardinalityClause) Cardinality() types.Cardinality {
+	return types.Cardinality{Literals: c.literals, Bound: uint(c.bound)}
+}
+
+func (c *CardinalityClause) Attach(f *WatchedFormula) {
+	c.formula = f
+}
+
+// The constraint is told when one of its literals is refuted
+func (c *CardinalityClause) Triggers() []types.Literal {
+	triggers := make([]types.Literal, len(c.literals))
+	for i, l := range c.literals {
+		triggers[i] = l.Negate()
+	}
+	return triggers
+}
+
+func (c *CardinalityClause) Assign(l types.Literal) {
+	c.refuted++
+	if c.refuted >= c.limit() {
+		c.Check()
+	}
+}
+
+func (c *CardinalityClause) Unassign(l types.Literal) {
+	c.refuted--
+}
+
+// Queues a conflict if too many literals are refuted, otherwise a reason for every implied literal
+func (c *CardinalityClause) Check() {
+	switch {
+	case c.refuted > c.limit():
+		c.formula.enqueue(&CardinalityReason{constraint: c})
+	case c.refuted == c.limit():
+		for _, l := range c.literals {
+			if c.formula.state(l) == UNASSIGNED {
+				c.formula.enqueue(&CardinalityReason{constraint: c, implied: l})
+			}
+		}
+	}
+}
+
+/*
+CardinalityReason is the clause explaining a literal implied by a CardinalityClause, or a conflict of it
+if the implied literal is 0.
+
+The clause has the implied literal and n-k refuted literals of the constraint, n-k+1 for a conflict.
+It is built on the first call of Original, when the refuted literals assigned before the implied literal
+are still assigned. The reason is stale once backjumping unassigns refuted literals, its type is then
+neither unit nor empty and the formula drops it.
+*/
+type CardinalityReason struct {
+	constraint *CardinalityClause
+	implied    types.Literal     // Literal implied by the constraint, 0 for a conflict
+	literals   types.Disjunction // Clause of the reason, nil till it is built
+}
+
+func (r *CardinalityReason) Type() types.ClauseType {
+	c := r.constraint
+	if r.implied == 0 {
+		if c.refuted > c.limit() {
+			return types.EMPTY_CLAUSE
+		}
+		return types.DECISION_CLAUSE
+	}
+
+	switch c.formula.state(r.implied) {
+	case ASSIGNED:
+		return types.SOLVED_CLAUSE
+	case REFUTED:
+		if c.refuted > c.limit() {
+			return types.EMPTY_CLAUSE
+		}
+	default:
+		if c.refuted >= c.limit() {
+			return types.UNIT_CLAUSE
+		}
+	}
+	return types.DECISION_CLAUSE
+}
+
+// The reason is built from the assignment of the formula, hence Apply does not change it
+func (r *CardinalityReason) Apply(l types.Literal) types.Clause {
+	return r
+}
+
+// The reason is built from the assignment of the formula, hence Undo does not change it
+func (r *CardinalityReason) Undo(l types.Literal) types.Clause {
+	return r
+}
+
+// The reason is built from the assignment of the formula, hence Reset does not change it
+func (r *CardinalityReason) Reset() types.Clause {
+	return r
+}
+
+func (r *CardinalityReason) Contains(l types.Literal) bool {
+	return r.implied != 0 && l == r.implied && r.constraint.formula.state(l) != REFUTED
+}
+
+func (r *CardinalityReason) IsSolved() bool {
+	return r.implied != 0 && r.constraint.formula.state(r.implied) == ASSIGNED
+}
+
+func (r *CardinalityReason) IsLearnt() bool {
+	return false
+}
+
+// Returns the implied literal unless it is refuted, every other literal of the reason is refuted
+func (r *CardinalityReason) Disjunction() types.Disjunction {
+	if r.implied == 0 || r.constraint.formula.state(r.implied) == REFUTED {
+		return nil
+	}
+	return types.Disjunction{r.implied}
+}
+
+/*
+Builds the clause of the reason. The refuted literals of a unit reason must have been assigned before
+the implied literal, while a conflict may take any refuted literals.
+*/
+func (r *CardinalityReason) Original() types.Disjunction {
+	if r.literals != nil {
+		return r.literals
+	}
+
+	c := r.constraint
+	f := c.formula
+	needed := c.limit()
+	if r.implied == 0 || f.state(r.implied) != ASSIGNED {
+		needed++
+	}
+
+	r.literals = types.Disjunction{}
+	if r.implied != 0 {
+		r.literals = append(r.literals, r.implied)
+	}
+	for _, l := range c.literals {
+		if needed == 0 {
+			break
+		}
+		if l == r.implied || f.state(l) != REFUTED {
+			continue
+		}
+		if f.state(r.implied) == ASSIGNED && f.stamps[l.Atom()] > f.stamps[r.implied.Atom()] {
+			continue
+		}
+		r.literals = append(r.literals, l)
+		needed--
+	}
+	return r.literals
+}
+
+/*
+AddCardinality adds the constraint that at least Bound of the literals are true to the Formula, it is
+kept for all later calls of Solve.
+
+A constraint with a bound of 1 is a clause and is added with AddClause, other constraints need a Formula
+which is a Constrainer. Proofs do not cover cardinality constraints, hence they cannot be added while a
+proof is written.
+*/
+func (solver *BaseCDCLSolver) AddCardinality(c types.Cardinality) error {
+	for _, l := range c.Literals {
+		if l == 0 {
+			return handler.Throw("Cardinality constraint contains 0, which is not a literal", nil)
+		}
+	}
+
+	constraint := ConstructCardinalityClause(c)
+	switch {
+	case constraint.bound == 0:
+		// The constraint always holds
+		return nil
+	case constraint.bound > len(constraint.literals):
+		// The constraint never holds
+		return solver.AddClause(nil)
+	case constraint.bound == 1:
+		return solver.AddClause(constraint.literals)
+	}
+
+	if solver.Proof != nil {
+		return handler.Throw("Proofs are not available with cardinality constraints", nil)
+	}
+	f, ok := solver.F.(Constrainer)
+	if !ok {
+		return handler.Throw("The formula does not support cardinality constraints", nil)
+	}
+
+	for _, l := range constraint.literals {
+		solver.Extend(uint(l.Atom()))
+	}
+	solver.Backjump(0)
+	f.Constrain(constraint)
+
+	logger.Info(fmt.Sprintf("Added cardinality constraint %v of %v", constraint.bound, constraint.literals))
+
+	return nil
+}
+
+// AddAtLeast adds the constraint that at least k of the literals are true, refer AddCardinality
+func (solver *BaseCDCLSolver) AddAtLeast(literals []types.Literal, k uint) error {
+	return solver.AddCardinality(types.Cardinality{Literals: literals, Bound: k})
+}
+
+// AddAtMost adds the constraint that at most k of the literals are true, which is at least n-k of their negations
+func (solver *BaseCDCLSolver) AddAtMost(literals []types.Literal, k uint) error {
+	literals = normalize(literals)
+	if k >= uint(len(literals)) {
+		return nil
+	}
+
+	negated := make(types.Disjunction, len(literals))
+	for i, l := range literals {
+		negated[i] = l.Negate()
+	}
+	return solver.AddCardinality(types.Cardinality{Literals: negated, Bound: uint(len(literals)) - k})
+}
@@ -0,0 +1,138 @@
+package solver_test
+
+import (
+	"math/rand"
+	"testing"
+
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func randomLiterals(random *rand.Rand, atoms int, n int) types.Disjunction {
+	var d types.Disjunction
+	for i := 0; i < n; i++ {
+		l := types.Literal(1 + random.Intn(atoms))
+		if random.Intn(2) == 0 {
+			l = l.Negate()
+		}
+		d = append(d, l)
+	}
+	return d
+}
+
+// Reports if some assignment satisfies the SATFile and the assumptions
+func bruteForceCardinality(sat types.SATFile, assumptions []types.Literal) bool {
+	model := make([]types.Literal, sat.AtomCount)
+	for bits := 0; bits < 1<<sat.AtomCount; bits++ {
+		for i := range model {
+			model[i] = types.Literal(i + 1)
+			if bits&(1<<i) == 0 {
+				model[i] = model[i].Negate()
+			}
+		}
+		holds := true
+		for _, l := range assumptions {
+			holds = holds && model[l.Atom()-1] == l
+		}
+		if violated, _ := solver.VerifyModel(sat, model); holds && len(violated) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCardinality(t *testing.T) {
+	random := rand.New(rand.NewSource(5))
+
+	for n := 0; n < 500; n++ {
+		atoms := 3 + random.Intn(8)
+		sat := types.SATFile{AtomCount: uint(atoms)}
+		for i := random.Intn(atoms); i > 0; i-- {
+			sat.Clauses = append(sat.Clauses, randomLiterals(random, atoms, 1+random.Intn(3)))
+		}
+		for i := 1 + random.Intn(4); i > 0; i-- {
+			literals := randomLiterals(random, atoms, 2+random.Intn(6))
+			sat.Cardinalities = append(sat.Cardinalities, types.Cardinality{Literals: literals, Bound: uint(random.Intn(len(literals) + 1))})
+		}
+		sat.ClauseCount = uint(len(sat.Clauses))
+
+		s, err := solver.InitializeBaseSolver(sat, solver.Options{Reduce: 20, Restart: solver.LUBY_RESTART, RestartBase: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		solution, err := s.Solve()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := bruteForceCardinality(sat, nil); expected != (solution == types.SATISFIABLE) {
+			t.Fatalf("%+v: expected satisfiable %v, found %v", sat, expected, solution)
+		}
+		if solution != types.SATISFIABLE {
+			continue
+		}
+		if violated, _ := solver.VerifyModel(sat, s.Model()); len(violated) > 0 {
+			t.Fatalf("%+v: model %v violates constraint %v", sat, s.Model(), violated[0])
+		}
+
+		// An at most constraint added later is kept for the calls under assumptions
+		literals := randomLiterals(random, atoms, 2+random.Intn(5))
+		k := random.Intn(len(literals))
+		if err = s.AddAtMost(literals, uint(k)); err != nil {
+			t.Fatal(err)
+		}
+		negated := make(types.Disjunction, 0, len(literals))
+		for _, l := range literals {
+			if !contains(negated, l.Negate()) {
+				negated = append(negated, l.Negate())
+			}
+		}
+		sat.Cardinalities = append(sat.Cardinalities, types.Cardinality{Literals: negated, Bound: uint(len(negated) - k)})
+		if len(negated) <= k {
+			sat.Cardinalities = sat.Cardinalities[:len(sat.Cardinalities)-1]
+		}
+
+		assumptions := randomLiterals(random, atoms, random.Intn(3))
+		if solution, err = s.Solve(assumptions...); err != nil {
+			t.Fatal(err)
+		}
+		if expected := bruteForceCardinality(sat, assumptions); expected != (solution == types.SATISFIABLE) {
+			t.Fatalf("%+v under %v: expected satisfiable %v, found %v", sat, assumptions, expected, solution)
+		}
+		if violated, _ := solver.VerifyModel(sat, s.Model()); solution == types.SATISFIABLE && len(violated) > 0 {
+			t.Fatalf("%+v: model %v violates constraint %v", sat, s.Model(), violated[0])
+		}
+	}
+}
+
+func TestCardinalityPigeonhole(t *testing.T) {
+	// 7 pigeons in 6 holes, every pigeon takes at least one hole and every hole at most one pigeon
+	const pigeons, holes = 7, 6
+	hole := func(p, h int) types.Literal { return types.Literal(p*holes + h + 1) }
+
+	s, err := solver.InitializeBaseSolver(types.SATFile{AtomCount: pigeons * holes}, solver.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for p := 0; p < pigeons; p++ {
+		var d types.Disjunction
+		for h := 0; h < holes; h++ {
+			d = append(d, hole(p, h))
+		}
+		if err = s.AddAtLeast(d, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for h := 0; h < holes; h++ {
+		var d types.Disjunction
+		for p := 0; p < pigeons; p++ {
+			d = append(d, hole(p, h))
+		}
+		if err = s.AddAtMost(d, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if solution, err := s.Solve(); err != nil || solution != types.UNSATISFIABLE {
+		t.Errorf("Expected UNSATISFIABLE, found %v %v", solution, err)
+	}
+}
@@ -0,0 +1,184 @@
+package solver
+
+/*
+The clausedb file keeps track of the quality of learnt clauses and periodically deletes the worst of them.
+*/
+
+import (
+	"sort"
+
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+Forgetter is implemented by Formulas from which learnt clauses can be deleted
+*/
+type Forgetter interface {
+	Forget(clauses []types.Clause) // Deletes the clauses from the Formula
+}
+
+/*
+Tier is an enum defining how long a learnt clause is retained based on its LBD
+*/
+type Tier uint
+
+const (
+	CORE_TIER  Tier = iota // Clauses with LBD of at most CORE_LBD, never deleted
+	TIER2_TIER             // Clauses with LBD of at most TIER2_LBD, kept as long as they are used
+	LOCAL_TIER             // All other clauses, the less active half is deleted on every reduction
+)
+
+const (
+	CORE_LBD  uint = 2 // Maximum LBD of a core clause
+	TIER2_LBD uint = 6 // Maximum LBD of a tier2 clause
+)
+
+// Returns the tier of a clause with the given LBD
+func tierOf(lbd uint) Tier {
+	if lbd <= CORE_LBD {
+		return CORE_TIER
+	} else if lbd <= TIER2_LBD {
+		return TIER2_TIER
+	}
+	return LOCAL_TIER
+}
+
+// LearntClause holds the metadata of a learnt clause recorded at learning time
+type LearntClause struct {
+	Clause   types.Clause
+	LBD      uint    // Literal block distance (glue) of the clause
+	Activity float64 // Bumped whenever the clause takes part in conflict analysis
+	Tier     Tier    // Retention tier of the clause
+	used     bool    // Set when the clause takes part in conflict analysis, cleared on reduction
+}
+
+/*
+ClauseDB keeps the metadata of all learnt clauses and decides which of them to delete.
+
+Reductions are due after a number of conflicts that grows arithmetically. On every reduction
+tier2 clauses which have not been used since the last reduction are moved to the local tier,
+and the less active half of the local clauses is deleted. Clauses which are the reason of a
+literal on the Trail are never deleted.
+*/
+type ClauseDB struct {
+	Learnts    []*LearntClause
+	Deleted    uint // No of learnt clauses deleted so far
+	Reductions uint // No of reductions done so far
+	entries    map[types.Clause]*LearntClause
+	increment  float64 // Value added to the activity of a bumped clause
+	decay      float64 // Factor by which clause activities decay after every conflict
+	interval   uint    // No of conflicts before the first reduction
+	conflicts  uint    // No of conflicts seen so far
+	next       uint    // No of conflicts at which the next reduction is due
+}
+
+// Constructs a ClauseDB which reduces first after the given no of conflicts
+func ConstructClauseDB(interval uint) *ClauseDB {
+	return &ClauseDB{
+		entries:   make(map[types.Clause]*LearntClause),
+		increment: 1,
+		decay:     0.999,
+		interval:  interval,
+		next:      interval,
+	}
+}
+
+// Records a newly learnt clause along with its LBD
+func (db *ClauseDB) Learn(c types.Clause, lbd uint) {
+	entry := &LearntClause{
+		Clause:   c,
+		LBD:      lbd,
+		Activity: db.increment,
+		Tier:     tierOf(lbd),
+		used:     true,
+	}
+	db.Learnts = append(db.Learnts, entry)
+	db.entries[c] = entry
+}
+
+/*
+Bump increases the activity of a learnt clause used in conflict analysis.
+The LBD of the clause is updated if it has improved, which can promote it to a better tier.
+*/
+func (db *ClauseDB) Bump(c types.Clause, lbd uint) {
+	entry, ok := db.entries[c]
+	if !ok {
+		return
+	}
+
+	entry.used = true
+	entry.Activity += db.increment
+	if lbd < entry.LBD {
+		entry.LBD = lbd
+		if t := tierOf(lbd); t < entry.Tier {
+			entry.Tier = t
+		}
+	}
+
+	// Rescale all activities before they overflow
+	if entry.Activity > 1e20 {
+		for _, e := range db.Learnts {
+			e.Activity *= 1e-20
+		}
+		db.increment *= 1e-20
+	}
+}
+
+// Decays clause activities and counts the conflict
+func (db *ClauseDB) Conflict() {
+	db.increment /= db.decay
+	db.conflicts++
+}
+
+// Returns true if a reduction is due
+func (db *ClauseDB) Due() bool {
+	return db.conflicts >= db.next
+}
+
+/*
+Reduce selects the learnt clauses to be deleted and removes them from the ClauseDB.
+The locked function reports whether a clause is the reason of a literal on the Trail.
+*/
+func (db *ClauseDB) Reduce(locked func(types.Clause) bool) []types.Clause {
+	var local []*LearntClause
+	for _, e := range db.Learnts {
+		if e.Tier == TIER2_TIER && !e.used {
+			e.Tier = LOCAL_TIER
+		}
+		if e.Tier == LOCAL_TIER && !locked(e.Clause) {
+			local = append(local, e)
+		}
+		e.used = false
+	}
+
+	// The less active half of the local clauses is deleted, ties are broken by LBD
+	sort.Slice(local, func(i, j int) bool {
+		if local[i].Activity != local[j].Activity {
+			return local[i].Activity < local[j].Activity
+		}
+		return local[i].LBD > local[j].LBD
+	})
+
+	var deleted []types.Clause
+	for _, e := range local[:len(local)/2] {
+		deleted = append(deleted, e.Clause)
+		delete(db.entries, e.Clause)
+	}
+
+	kept := db.Learnts[:0]
+	for _, e := range db.Learnts {
+		if _, ok := db.entries[e.Clause]; ok {
+			kept = append(kept, e)
+		}
+	}
+	for j := len(kept); j < len(db.Learnts); j++ {
+		db.Learnts[j] = nil
+	}
+	db.Learnts = kept
+
+	db.Deleted += uint(len(deleted))
+	db.Reductions++
+	db.next = db.conflicts + db.interval*(db.Reductions+1)
+
+	return deleted
+}
@@ -0,0 +1,69 @@
+package solver_test
+
+import (
+	"testing"
+
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestReduce(t *testing.T) {
+	f := getWatchedFormula(nil, 10)
+	db := solver.ConstructClauseDB(1)
+
+	core := f.Construct(types.Disjunction{1, 2}, true)
+	tier2 := f.Construct(types.Disjunction{1, 3, 4}, true)
+	locked := f.Construct(types.Disjunction{5, 6, 7}, true)
+	var local []types.Clause
+	for i := 0; i < 4; i++ {
+		local = append(local, f.Construct(types.Disjunction{8, 9, types.Literal(-i - 1)}, true))
+	}
+
+	db.Learn(core, 2)
+	db.Learn(tier2, 5)
+	db.Learn(locked, 9)
+	for i, c := range local {
+		db.Learn(c, 9)
+		db.Conflict()
+		// The clauses learnt later are more active
+		for j := 0; j < i; j++ {
+			db.Bump(c, 9)
+		}
+	}
+
+	if !db.Due() {
+		t.Errorf("Reduction should be due")
+	}
+
+	deleted := db.Reduce(func(c types.Clause) bool { return c == locked })
+	if len(deleted) != 2 || deleted[0] != local[0] || deleted[1] != local[1] {
+		t.Errorf("Expected the two least active local clauses to be deleted, found %v", deleted)
+	}
+
+	// The unused tier2 clause is demoted and deleted on the next reduction
+	deleted = db.Reduce(func(c types.Clause) bool { return c == locked })
+	if len(deleted) != 1 || deleted[0] != tier2 {
+		t.Errorf("Expected the unused tier2 clause to be deleted, found %v", deleted)
+	}
+
+	if db.Deleted != 3 || len(db.Learnts) != 4 {
+		t.Errorf("Expected 3 deleted and 4 kept clauses, found %v and %v", db.Deleted, len(db.Learnts))
+	}
+}
+
+func TestForget(t *testing.T) {
+	f := getWatchedFormula([]types.Disjunction{{1, 2, 3}}, 3)
+	learnt := f.Construct(types.Disjunction{-1, -2}, true)
+	f.Learn(learnt)
+
+	f.Forget([]types.Clause{learnt})
+	if len(f.Clauses) != 1 {
+		t.Errorf("Expected the learnt clause to be deleted, found %v", f.Print())
+	}
+
+	// The deleted clause must not propagate
+	f.Assign(1)
+	if c := f.NextClause(); c.Type() != types.DECISION_CLAUSE {
+		t.Errorf("Expected decision clause, found %v", c.Disjunction())
+	}
+}
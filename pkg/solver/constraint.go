@@ -0,0 +1,65 @@
+package solver
+
+/*
+The constraint file lets the WatchedFormula propagate constraints which are not disjunctions of literals.
+
+Conflict analysis only deals with clauses, hence a constraint explains every literal it implies, and every
+conflict it finds, with a clause implied by the constraint. The explanation clause is queued like a unit or
+empty clause of the formula and becomes the reason of the implied literal on the Trail.
+*/
+
+import (
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+Constraint is a Clause of the WatchedFormula which is not a disjunction of literals.
+
+Constrain attaches the constraint to the formula whose assignment it reads. The formula calls Assign and
+Unassign for the literals returned by Triggers. Reset recomputes the state
+of the constraint from the current assignment of the formula, and Check queues the explanation of every
+literal implied and of a conflict under the current assignment.
+*/
+type Constraint interface {
+	types.Clause
+	Attach(f *WatchedFormula)  // Makes the constraint read the assignment of the formula
+	Triggers() []types.Literal // Literals whose assignment the constraint is told of
+	Assign(l types.Literal)    // Called after a trigger is assigned
+	Unassign(l types.Literal)  // Called before a trigger is unassigned
+	Check()                    // Queues the explanations of the current assignment
+}
+
+/*
+Constrainer is implemented by Formulas which can propagate Constraints
+*/
+type Constrainer interface {
+	Constrain(c Constraint) // Adds the constraint to the Formula
+}
+
+/*
+Constrain adds a constraint to the formula. Its state is computed from the current assignment, so
+literals it implies are found by the next call of NextClause.
+*/
+func (f *WatchedFormula) Constrain(c Constraint) {
+	c.Attach(f)
+	f.constraints = append(f.constraints, c)
+	for _, l := range c.Triggers() {
+		f.Extend(uint(l.Atom()))
+		f.triggers[index(l)] = append(f.triggers[index(l)], c)
+	}
+	c.Reset()
+	c.Check()
+}
+
+/*
+Explain queues a clause implied by a constraint, which is either unit on the literal the constraint
+implies or empty for a conflict. The clause is not added to the formula.
+*/
+func (f *WatchedFormula) Explain(d types.Disjunction) {
+	f.enqueue(&WatchedClause{literals: d, formula: f})
+}
+
+// Value returns the literal of the atom in the current assignment of the formula, 0 if it is unassigned
+func (f *WatchedFormula) Value(a types.Atom) types.Literal {
+	return f.values[a]
+}
@@ -0,0 +1,139 @@
+package solver
+
+/*
+The core file finds the clauses of the SATFile responsible for an UNSATISFIABLE result.
+
+Clause i of the SATFile is extended with the selector atom s_i, the atom i+1 after the atoms of the
+SATFile, and Solve assumes -s_i before its own assumptions. The clauses whose selector assumptions
+failed form the unsatisfiable core. A clause is disabled by not assuming its selector, which lets
+ShrinkCore search for a minimal unsatisfiable subset (MUS) with the incremental solver.
+*/
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+WithSelectors returns a copy of the SATFile where every clause has its selector atom, the clauses of
+the SATFile are not modified.
+*/
+func WithSelectors(sat types.SATFile) types.SATFile {
+	selected := types.SATFile{
+		AtomCount:   sat.AtomCount + uint(len(sat.Clauses)),
+		ClauseCount: sat.ClauseCount,
+		Clauses:     make([]types.Disjunction, len(sat.Clauses)),
+	}
+	for i, d := range sat.Clauses {
+		selector := types.Literal(sat.AtomCount + uint(i) + 1)
+		selected.Clauses[i] = append(append(make(types.Disjunction, 0, len(d)+1), d...), selector)
+	}
+	return selected
+}
+
+/*
+Selected returns the assumptions enabling the given clauses of the SATFile, every clause if clauses is nil.
+It is empty if cores are disabled.
+*/
+func (solver *BaseCDCLSolver) Selected(clauses []int) []types.Literal {
+	if solver.selectorCount == 0 {
+		return nil
+	}
+
+	var selectors []types.Literal
+	if clauses == nil {
+		selectors = make([]types.Literal, solver.selectorCount)
+		for i := range selectors {
+			selectors[i] = types.Literal(solver.selectorBase + uint(i) + 1).Negate()
+		}
+	} else {
+		selectors = make([]types.Literal, len(clauses))
+		for i, c := range clauses {
+			selectors[i] = types.Literal(solver.selectorBase + uint(c) + 1).Negate()
+		}
+	}
+	return selectors
+}
+
+/*
+SplitFailed splits failed assumptions into the assumptions given to Solve and the sorted indices
+of the clauses whose selector assumptions failed
+*/
+func (solver *BaseCDCLSolver) SplitFailed(failed []types.Literal) ([]types.Literal, []int) {
+	var (
+		assumptions []types.Literal
+		core        []int
+	)
+	for _, l := range failed {
+		atom := uint(l.Atom())
+		if l < 0 && atom > solver.selectorBase && atom <= solver.selectorBase+solver.selectorCount {
+			core = append(core, int(atom-solver.selectorBase-1))
+		} else {
+			assumptions = append(assumptions, l)
+		}
+	}
+	sort.Ints(core)
+	return assumptions, core
+}
+
+/*
+UnsatCore returns the indices into SATFile.Clauses of the clauses which, with the failed assumptions,
+made the last call of Solve UNSATISFIABLE. It is nil if cores are disabled or the last result was not
+UNSATISFIABLE.
+
+The core is not guaranteed to be minimal, refer ShrinkCore. It is empty if the clauses added with
+AddClause are unsatisfiable on their own.
+*/
+func (solver *BaseCDCLSolver) UnsatCore() []int {
+	return solver.core
+}
+
+/*
+ShrinkCore shrinks the core of the last UNSATISFIABLE result to a minimal unsatisfiable subset, so that
+removing any of its clauses makes the formula satisfiable under the assumptions, which must be the ones
+the last result was found with.
+
+Each clause of the core is disabled in turn. If the formula stays UNSATISFIABLE the clause is dropped
+along with the other clauses missing from the new core, otherwise the clause is necessary and is kept.
+*/
+func (solver *BaseCDCLSolver) ShrinkCore(assumptions ...types.Literal) ([]int, error) {
+	return solver.ShrinkCoreContext(context.Background(), assumptions...)
+}
+
+/*
+ShrinkCoreContext shrinks the core like ShrinkCore till the context or the limits of the solver stop
+a call of Solve. The core found so far is returned then, which is unsatisfiable but may not be minimal.
+*/
+func (solver *BaseCDCLSolver) ShrinkCoreContext(ctx context.Context, assumptions ...types.Literal) ([]int, error) {
+	solver.startClock()
+	defer solver.stopClock()
+
+	core := solver.core
+
+	for i := 0; i < len(core); {
+		candidate := append(append([]int{}, core[:i]...), core[i+1:]...)
+
+		solution, err := solver.solveWith(ctx, solver.Selected(candidate), assumptions)
+		if err != nil {
+			return nil, err
+		}
+		if solution == types.UNKNOWN {
+			break
+		}
+
+		if solution == types.UNSATISFIABLE {
+			logger.Info(fmt.Sprintf("Dropped clause %v from the core", core[i]))
+			// The clauses before i are necessary for every subset of the core, hence the new core keeps them
+			core = solver.core
+		} else {
+			i++
+		}
+	}
+
+	solver.failed, solver.core, solver.model = nil, core, nil
+	return core, nil
+}
@@ -0,0 +1,119 @@
+package solver_test
+
+import (
+	"io"
+	"math/rand"
+	"testing"
+
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestUnsatCore(t *testing.T) {
+	sat := types.SATFile{
+		AtomCount: 3,
+		Clauses: []types.Disjunction{
+			{1, 2},
+			{-1},
+			{3},
+			{-2},
+			{2, -3},
+		},
+	}
+
+	s, err := solver.InitializeBaseSolver(sat, solver.Options{Core: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solution, _ := s.Solve(); solution != types.UNSATISFIABLE {
+		t.Fatalf("Expected UNSATISFIABLE, found %v", solution)
+	}
+	if core := s.UnsatCore(); len(core) == 0 || len(s.Failed()) != 0 {
+		t.Fatalf("Expected a core without failed assumptions, found %v %v", core, s.Failed())
+	}
+
+	core, err := s.ShrinkCore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(core) != 3 || (core[0] != 0 || core[1] != 1 || core[2] != 3) && (core[0] != 2 || core[1] != 3 || core[2] != 4) {
+		t.Errorf("Expected the core [0 1 3] or [2 3 4], found %v", core)
+	}
+	if len(sat.Clauses[0]) != 2 {
+		t.Errorf("Expected the clauses of the SATFile to be unmodified, found %v", sat.Clauses)
+	}
+
+	// The selector atoms are not failed assumptions
+	if solution, _ := s.Solve(-3); solution != types.UNSATISFIABLE {
+		t.Fatalf("Expected UNSATISFIABLE, found %v", solution)
+	}
+	for _, l := range s.Failed() {
+		if l != -3 {
+			t.Errorf("Expected failed assumptions [-3], found %v", s.Failed())
+		}
+	}
+
+	if _, err = solver.InitializeBaseSolver(sat, solver.Options{Core: true, Proof: io.Discard}); err == nil {
+		t.Errorf("Expected cores and proofs to be exclusive")
+	}
+}
+
+// Checks that cores are unsatisfiable and that shrunk cores are minimal against brute force
+func TestUnsatCoreRandom(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	literal := func(n int) types.Literal {
+		l := types.Literal(random.Intn(n) + 1)
+		if random.Intn(2) == 0 {
+			return l.Negate()
+		}
+		return l
+	}
+
+	for round := 0; round < 40; round++ {
+		n := 6
+		var clauses []types.Disjunction
+		for i := 0; i < 30; i++ {
+			clauses = append(clauses, types.Disjunction{literal(n), literal(n), literal(n)})
+		}
+
+		s, err := solver.InitializeBaseSolver(types.SATFile{
+			AtomCount: uint(n),
+			Clauses:   clauses,
+		}, solver.Options{Core: true, Restart: solver.LUBY_RESTART, RestartBase: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if solution, _ := s.Solve(); solution != types.UNSATISFIABLE {
+			if bruteForce(clauses, nil, n) != types.SATISFIABLE {
+				t.Fatalf("Expected UNSATISFIABLE, found %v", solution)
+			}
+			continue
+		}
+
+		subset := func(core []int) []types.Disjunction {
+			var d []types.Disjunction
+			for _, i := range core {
+				d = append(d, clauses[i])
+			}
+			return d
+		}
+		if bruteForce(subset(s.UnsatCore()), nil, n) != types.UNSATISFIABLE {
+			t.Fatalf("Core %v is satisfiable", s.UnsatCore())
+		}
+
+		core, err := s.ShrinkCore()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bruteForce(subset(core), nil, n) != types.UNSATISFIABLE {
+			t.Fatalf("Shrunk core %v is satisfiable", core)
+		}
+		for i := range core {
+			rest := append(append([]int{}, core[:i]...), core[i+1:]...)
+			if bruteForce(subset(rest), nil, n) != types.SATISFIABLE {
+				t.Fatalf("Core %v is not minimal, %v is unsatisfiable", core, rest)
+			}
+		}
+	}
+}
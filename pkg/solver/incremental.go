@@ -0,0 +1,145 @@
+package solver
+
+/*
+The incremental file lets the solver be called many times on a growing Formula under different assumptions.
+
+Assumptions are decided before any other atom, the i-th assumption at decision level i+1. An assumption
+which already holds gets a decision level without a decision literal, so that backjumping below the level
+of an assumption always undoes it. When an assumption is found to be false, the assumptions it depends on
+are collected from the implication graph as the final conflict.
+*/
+
+import (
+	"fmt"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+Extender is implemented by Formulas which need to know when atoms are added
+*/
+type Extender interface {
+	Extend(atomCount uint) // Extends the Formula to hold the given number of atoms
+}
+
+/*
+AddClause adds a clause to the Formula, it is kept for all later calls of Solve.
+
+The Trail is backjumped to level 0 and the clause is asserted with the literals assigned at level 0,
+so a clause which is unit or empty is found by the next call of Solve. Atoms which are not part of the
+Formula yet are added to it.
+*/
+func (solver *BaseCDCLSolver) AddClause(d types.Disjunction) error {
+	for _, l := range d {
+		if l == 0 {
+			return handler.Throw("Clause contains 0, which is not a literal", nil)
+		}
+		solver.Extend(uint(l.Atom()))
+	}
+
+	solver.Backjump(0)
+
+	clause := solver.Construct(append(types.Disjunction{}, d...), false)
+	for _, l := range solver.Trail.Literals {
+		clause = clause.Apply(l)
+	}
+	solver.F = solver.F.Learn(clause)
+
+	logger.Info(fmt.Sprintf("Added clause %v", d))
+
+	return nil
+}
+
+/*
+Extend adds atoms to the solver till it holds the given number of atoms
+*/
+func (solver *BaseCDCLSolver) Extend(atomCount uint) {
+	if atomCount <= solver.AtomCount {
+		return
+	}
+
+	solver.Trail.Extend(atomCount)
+	if f, ok := solver.F.(Extender); ok {
+		f.Extend(atomCount)
+	}
+	solver.Brancher.Extend(atomCount)
+	solver.Phase.Extend(atomCount)
+	solver.marks = append(solver.marks, make([]uint8, atomCount-solver.AtomCount)...)
+
+	solver.AtomCount = atomCount
+}
+
+/*
+Assume decides the assumption of the next decision level.
+
+An assumption which already holds only opens a new decision level. Returns false if the assumption
+is false, in which case the assumptions responsible are stored for Failed.
+*/
+func (solver *BaseCDCLSolver) Assume() bool {
+	lit := solver.Assumptions[solver.Trail.DecisionLevel()]
+
+	switch solver.Trail.Value(lit.Atom()) {
+	case lit:
+		solver.Trail.NewLevel()
+	case lit.Negate():
+		logger.Info(fmt.Sprintf("Assumption %v failed", lit))
+		solver.failed = solver.AnalyseFinal(lit)
+		return false
+	default:
+		logger.Info(fmt.Sprintf("Assuming %v", lit))
+		solver.Trail.Pushback(ModelElement{
+			Literal:  lit,
+			Decision: true,
+		})
+		solver.Propagate()
+	}
+
+	return true
+}
+
+/*
+AnalyseFinal returns the assumptions which together with the Formula imply the negation of the given
+assumption, including the assumption itself.
+
+Every decision on the Trail is an assumption at this point, so the reasons of the negation are
+followed back till decisions, skipping the literals assigned at level 0.
+*/
+func (solver *BaseCDCLSolver) AnalyseFinal(lit types.Literal) []types.Literal {
+	failed := []types.Literal{lit}
+
+	atom := lit.Atom()
+	if solver.Trail.Levels[atom] == 0 {
+		return failed
+	}
+
+	seen := map[types.Atom]bool{atom: true}
+	for i := len(solver.Trail.Literals) - 1; i >= solver.Trail.Decisions[0]; i-- {
+		l := solver.Trail.Literals[i]
+		if !seen[l.Atom()] {
+			continue
+		}
+
+		if reason := solver.Trail.Reasons[l.Atom()]; reason == nil {
+			failed = append(failed, l)
+		} else {
+			for _, r := range reason.Original() {
+				if solver.Trail.Levels[r.Atom()] > 0 {
+					seen[r.Atom()] = true
+				}
+			}
+		}
+	}
+
+	return failed
+}
+
+/*
+Failed returns the assumptions responsible for the last UNSATISFIABLE result of Solve.
+
+It is empty if the Formula is unsatisfiable without any assumptions.
+*/
+func (solver *BaseCDCLSolver) Failed() []types.Literal {
+	return solver.failed
+}
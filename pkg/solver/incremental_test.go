@@ -0,0 +1,175 @@
+package solver_test
+
+import (
+	"math/rand"
+	"testing"
+
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestAssumptions(t *testing.T) {
+	s, err := solver.InitializeBaseSolver(types.SATFile{
+		AtomCount: 4,
+		Clauses: []types.Disjunction{
+			{-1, 2},
+			{-2, 3},
+			{4, -4},
+		},
+	}, solver.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if solution, _ := s.Solve(4, 1, -3); solution != types.UNSATISFIABLE {
+		t.Fatalf("Expected UNSATISFIABLE, found %v", solution)
+	}
+	failed := types.Disjunction(s.Failed())
+	if len(failed) != 2 || !contains(failed, 1) || !contains(failed, -3) {
+		t.Errorf("Expected failed assumptions [1 -3], found %v", failed)
+	}
+
+	if solution, _ := s.Solve(1); solution != types.SATISFIABLE {
+		t.Fatalf("Expected SATISFIABLE, found %v", solution)
+	}
+	if s.Value(3) != 3 {
+		t.Errorf("Expected 3 to be implied by assumption 1")
+	}
+
+	// Clauses added between calls are kept, and may introduce new atoms
+	if err = s.AddClause(types.Disjunction{-3, 5}); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.AddClause(types.Disjunction{-5}); err != nil {
+		t.Fatal(err)
+	}
+	if solution, _ := s.Solve(1); solution != types.UNSATISFIABLE {
+		t.Fatalf("Expected UNSATISFIABLE, found %v", solution)
+	}
+	if failed = s.Failed(); len(failed) != 1 || failed[0] != 1 {
+		t.Errorf("Expected failed assumptions [1], found %v", failed)
+	}
+
+	if solution, _ := s.Solve(); solution != types.SATISFIABLE {
+		t.Fatalf("Expected SATISFIABLE, found %v", solution)
+	}
+
+	if err = s.AddClause(types.Disjunction{}); err != nil {
+		t.Fatal(err)
+	}
+	if solution, _ := s.Solve(); solution != types.UNSATISFIABLE || len(s.Failed()) != 0 {
+		t.Errorf("Expected UNSATISFIABLE without failed assumptions, found %v %v", solution, s.Failed())
+	}
+}
+
+// Checks every call of Solve on a growing random formula against brute force
+func TestIncrementalRandom(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	literal := func(n int) types.Literal {
+		l := types.Literal(random.Intn(n) + 1)
+		if random.Intn(2) == 0 {
+			return l.Negate()
+		}
+		return l
+	}
+
+	for _, formula := range []solver.FormulaType{solver.WATCHED_FORMULA, solver.BASE_FORMULA} {
+		for round := 0; round < 20; round++ {
+			n := 8
+			var clauses []types.Disjunction
+			for i := 0; i < 20; i++ {
+				clauses = append(clauses, types.Disjunction{literal(n), literal(n), literal(n)})
+			}
+
+			s, err := solver.InitializeBaseSolver(types.SATFile{
+				AtomCount: uint(n),
+				Clauses:   clauses,
+			}, solver.Options{Formula: formula, Restart: solver.LUBY_RESTART, RestartBase: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for call := 0; call < 10; call++ {
+				if call%3 == 2 {
+					// Clauses added later may use a new atom
+					d := types.Disjunction{literal(n + 1), literal(n)}
+					clauses = append(clauses, d)
+					if err = s.AddClause(d); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				var assumptions []types.Literal
+				for i := random.Intn(4); i > 0; i-- {
+					assumptions = append(assumptions, literal(n+1))
+				}
+
+				solution, err := s.Solve(assumptions...)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if expected := bruteForce(clauses, assumptions, n+1); solution != expected {
+					t.Fatalf("Expected %v under %v, found %v", expected, assumptions, solution)
+				}
+
+				if solution == types.SATISFIABLE {
+					if len(s.Model()) != int(s.AtomCount) {
+						t.Fatalf("Expected a model of %v atoms, found %v", s.AtomCount, s.Model())
+					}
+					for _, l := range assumptions {
+						if s.Value(l.Atom()) != l {
+							t.Fatalf("Model does not satisfy assumption %v", l)
+						}
+					}
+					for _, d := range clauses {
+						satisfied := false
+						for _, l := range d {
+							satisfied = satisfied || s.Value(l.Atom()) == l
+						}
+						if !satisfied {
+							t.Fatalf("Model does not satisfy %v", d)
+						}
+					}
+				} else {
+					if s.Model() != nil {
+						t.Fatalf("Expected no model, found %v", s.Model())
+					}
+					failed := s.Failed()
+					for _, l := range failed {
+						if !contains(assumptions, l) {
+							t.Fatalf("Failed assumption %v is not one of %v", l, assumptions)
+						}
+					}
+					if bruteForce(clauses, failed, n+1) != types.UNSATISFIABLE {
+						t.Fatalf("Failed assumptions %v of %v are satisfiable", failed, assumptions)
+					}
+				}
+			}
+		}
+	}
+}
+
+// Tries every assignment of the atoms
+func bruteForce(clauses []types.Disjunction, assumptions []types.Literal, atomCount int) types.Solution {
+	for bits := 0; bits < 1<<atomCount; bits++ {
+		holds := func(l types.Literal) bool {
+			return (bits>>(int(l.Atom())-1)&1 == 1) == (l > 0)
+		}
+		ok := true
+		for _, l := range assumptions {
+			ok = ok && holds(l)
+		}
+		for _, d := range clauses {
+			satisfied := false
+			for _, l := range d {
+				satisfied = satisfied || holds(l)
+			}
+			ok = ok && satisfied
+		}
+		if ok {
+			return types.SATISFIABLE
+		}
+	}
+	return types.UNSATISFIABLE
+}
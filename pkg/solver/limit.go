@@ -0,0 +1,70 @@
+package solver
+
+/*
+The limit file stops a call of Solve when its context is done or when it exceeds its limits, in which
+case the result is UNKNOWN and Stopped tells why. The Trail and the learnt clauses are kept, so the
+next call of Solve continues the search.
+*/
+
+import (
+	"context"
+	"fmt"
+
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+)
+
+/*
+StopReason is an enum defining why a call of Solve stopped before finding a result
+*/
+type StopReason uint
+
+const (
+	NOT_STOPPED    StopReason = iota // The call found a result or failed with an error
+	TIMEOUT                          // The deadline of the context passed
+	INTERRUPTED                      // The context was cancelled
+	CONFLICT_LIMIT                   // The call reached the conflict limit
+	DECISION_LIMIT                   // The call reached the decision limit
+)
+
+func (r StopReason) String() string {
+	switch r {
+	case TIMEOUT:
+		return "timeout"
+	case INTERRUPTED:
+		return "interrupted"
+	case CONFLICT_LIMIT:
+		return "conflict limit"
+	case DECISION_LIMIT:
+		return "decision limit"
+	}
+	return "not stopped"
+}
+
+/*
+Stop checks the context and the limits of the current call of Solve, which started after the given
+no of conflicts and decisions. Returns true if the search must stop, after storing the reason.
+*/
+func (solver *BaseCDCLSolver) Stop(ctx context.Context, conflicts uint, decisions uint) bool {
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		solver.stopped = TIMEOUT
+	case ctx.Err() != nil:
+		solver.stopped = INTERRUPTED
+	case solver.ConflictLimit > 0 && solver.Stats.Conflicts-conflicts >= solver.ConflictLimit:
+		solver.stopped = CONFLICT_LIMIT
+	case solver.DecisionLimit > 0 && solver.Stats.Decisions-decisions >= solver.DecisionLimit:
+		solver.stopped = DECISION_LIMIT
+	default:
+		return false
+	}
+
+	logger.Info(fmt.Sprintf("Stopped by %v", solver.stopped))
+	return true
+}
+
+/*
+Stopped returns why the last call of Solve returned UNKNOWN, NOT_STOPPED if it found a result
+*/
+func (solver *BaseCDCLSolver) Stopped() StopReason {
+	return solver.stopped
+}
@@ -0,0 +1,64 @@
+package solver_test
+
+import (
+	"context"
+	"testing"
+
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+// Places n+1 pigeons into n holes, atom p*n+h+1 puts pigeon p into hole h
+func pigeonhole(n int) types.SATFile {
+	sat := types.SATFile{AtomCount: uint((n + 1) * n)}
+	atom := func(p, h int) types.Literal {
+		return types.Literal(p*n + h + 1)
+	}
+	for p := 0; p <= n; p++ {
+		var d types.Disjunction
+		for h := 0; h < n; h++ {
+			d = append(d, atom(p, h))
+		}
+		sat.Clauses = append(sat.Clauses, d)
+	}
+	for h := 0; h < n; h++ {
+		for p := 0; p <= n; p++ {
+			for q := p + 1; q <= n; q++ {
+				sat.Clauses = append(sat.Clauses, types.Disjunction{atom(p, h).Negate(), atom(q, h).Negate()})
+			}
+		}
+	}
+	return sat
+}
+
+func TestSolveContext(t *testing.T) {
+	s, err := solver.InitializeBaseSolver(pigeonhole(5), solver.Options{Conflicts: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if solution, err := s.Solve(); solution != types.UNKNOWN || err != nil || s.Stopped() != solver.CONFLICT_LIMIT {
+		t.Fatalf("Expected UNKNOWN by the conflict limit, found %v %v %v", solution, err, s.Stopped())
+	}
+	if s.Stats.Conflicts != 3 {
+		t.Errorf("Expected 3 conflicts, found %v", s.Stats.Conflicts)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if solution, _ := s.SolveContext(ctx); solution != types.UNKNOWN || s.Stopped() != solver.INTERRUPTED {
+		t.Fatalf("Expected UNKNOWN by cancellation, found %v %v", solution, s.Stopped())
+	}
+
+	s.ConflictLimit = 0
+	s.DecisionLimit = 2
+	if solution, _ := s.Solve(); solution != types.UNKNOWN || s.Stopped() != solver.DECISION_LIMIT {
+		t.Fatalf("Expected UNKNOWN by the decision limit, found %v %v", solution, s.Stopped())
+	}
+
+	// The search continues from the clauses learnt before it was stopped
+	s.DecisionLimit = 0
+	if solution, _ := s.Solve(); solution != types.UNSATISFIABLE || s.Stopped() != solver.NOT_STOPPED {
+		t.Fatalf("Expected UNSATISFIABLE, found %v %v", solution, s.Stopped())
+	}
+}
@@ -0,0 +1,160 @@
+package solver
+
+/*
+The minimize file removes redundant literals from learnt clauses using the implication graph of the Trail.
+*/
+
+import (
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+BinaryIndex is implemented by Formulas which can list the binary clauses containing a literal
+*/
+type BinaryIndex interface {
+	Binaries(l types.Literal) []types.Literal // Returns the other literal of every binary clause containing l
+}
+
+// Marks used on atoms while minimizing a clause
+const (
+	UNMARKED  uint8 = iota
+	IN_CLAUSE       // The atom occurs in the clause being minimized
+	REMOVABLE       // The atom is implied by atoms in the clause
+	POISONED        // The atom is known not to be implied by atoms in the clause
+)
+
+/*
+Minimize removes redundant literals from a learnt clause.
+
+With recursive minimization, a literal is removed if the reason of its negation only contains
+literals which are in the clause, assigned at level 0, or recursively removable themselves.
+With binary minimization, a literal is removed if a binary clause together with the asserting
+literal of the clause self-subsumes it.
+
+The asserting literal, which is the only literal at the highest level, is never removed.
+*/
+func (solver *BaseCDCLSolver) Minimize(d types.Disjunction) types.Disjunction {
+	var (
+		clause   types.Disjunction
+		touched  []types.Atom
+		abstract uint64 // Set of the decision levels in the clause, hashed to 64 bits
+		top      uint   // Highest decision level in the clause
+	)
+
+	for _, l := range d {
+		a := l.Atom()
+		if solver.marks[a] == IN_CLAUSE {
+			continue
+		}
+		solver.marks[a] = IN_CLAUSE
+		touched = append(touched, a)
+		clause = append(clause, l)
+
+		abstract |= 1 << (solver.Trail.Levels[a] % 64)
+		if solver.Trail.Levels[a] > top {
+			top = solver.Trail.Levels[a]
+		}
+	}
+
+	if solver.MinimizeRecursive {
+		kept := clause[:0]
+		for _, l := range clause {
+			a := l.Atom()
+			if solver.Trail.Levels[a] == top || !solver.removable(a, abstract, &touched) {
+				kept = append(kept, l)
+			}
+		}
+		clause = kept
+	}
+
+	if f, ok := solver.F.(BinaryIndex); ok && solver.MinimizeBinary {
+		clause = solver.minimizeBinary(clause, top, f)
+	}
+
+	for _, a := range touched {
+		solver.marks[a] = UNMARKED
+	}
+
+	return clause
+}
+
+/*
+Checks if an atom is implied by the atoms in the clause by walking its reasons depth first.
+Atoms found to be removable or poisoned are cached in the marks for the rest of the minimization.
+*/
+func (solver *BaseCDCLSolver) removable(atom types.Atom, abstract uint64, touched *[]types.Atom) bool {
+	if solver.Trail.Reasons[atom] == nil {
+		return false
+	}
+
+	stack := []types.Atom{atom}
+	var visited []types.Atom
+
+	for len(stack) > 0 {
+		x := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, r := range solver.Trail.Reasons[x].Original() {
+			b := r.Atom()
+			if b == x || solver.Trail.Levels[b] == 0 {
+				continue
+			}
+			if m := solver.marks[b]; m == IN_CLAUSE || m == REMOVABLE {
+				continue
+			}
+
+			level := solver.Trail.Levels[b]
+			if solver.marks[b] == POISONED || solver.Trail.Reasons[b] == nil || abstract&(1<<(level%64)) == 0 {
+				// Every atom visited so far depends on an atom outside the clause
+				for _, v := range visited {
+					solver.marks[v] = POISONED
+				}
+				if solver.marks[atom] != IN_CLAUSE {
+					solver.marks[atom] = POISONED
+				}
+				return false
+			}
+
+			solver.marks[b] = REMOVABLE
+			visited = append(visited, b)
+			*touched = append(*touched, b)
+			stack = append(stack, b)
+		}
+	}
+
+	return true
+}
+
+/*
+Removes the literals -x of the clause for which a binary clause (l x) exists, where l is the asserting
+literal. Resolving the clause with (l x) gives the clause without -x, which subsumes the clause.
+*/
+func (solver *BaseCDCLSolver) minimizeBinary(clause types.Disjunction, top uint, f BinaryIndex) types.Disjunction {
+	var asserting types.Literal
+	inClause := make(map[types.Literal]bool)
+	for _, l := range clause {
+		inClause[l] = true
+		if solver.Trail.Levels[l.Atom()] == top {
+			asserting = l
+		}
+	}
+
+	removed := make(map[types.Literal]bool)
+	for _, x := range f.Binaries(asserting) {
+		if inClause[x.Negate()] && x.Negate() != asserting {
+			removed[x.Negate()] = true
+		}
+	}
+
+	if len(removed) == 0 {
+		return clause
+	}
+
+	kept := clause[:0]
+	for _, l := range clause {
+		if !removed[l] {
+			kept = append(kept, l)
+		}
+	}
+	return kept
+}
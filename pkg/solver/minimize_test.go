@@ -0,0 +1,58 @@
+package solver_test
+
+import (
+	"testing"
+
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func getMinimizeSolver(t *testing.T, options solver.Options) solver.BaseCDCLSolver {
+	reason := types.Disjunction{-1, 2}
+	s, err := solver.InitializeBaseSolver(types.SATFile{
+		AtomCount: 5,
+		Clauses: []types.Disjunction{
+			reason,
+			{-3, 5},
+			{1, 2, 3, 4, 5},
+		},
+	}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// [1d 2 5d 3d] is the model we apply
+	Assign(&s, 1, true, nil)
+	Assign(&s, 2, false, s.Construct(reason, false))
+	Assign(&s, 5, true, nil)
+	Assign(&s, 3, true, nil)
+
+	return s
+}
+
+func TestMinimizeRecursive(t *testing.T) {
+	s := getMinimizeSolver(t, solver.Options{Minimize: true})
+
+	d := s.Minimize(types.Disjunction{-1, -2, -5, -3, -2})
+	if len(d) != 3 || !contains(d, -1) || !contains(d, -5) || !contains(d, -3) {
+		t.Errorf("Expected [-1 -5 -3], found %v", d)
+	}
+}
+
+func TestMinimizeBinary(t *testing.T) {
+	s := getMinimizeSolver(t, solver.Options{Minimize: true, Binary: true})
+
+	d := s.Minimize(types.Disjunction{-1, -2, -5, -3})
+	if len(d) != 2 || !contains(d, -1) || !contains(d, -3) {
+		t.Errorf("Expected [-1 -3], found %v", d)
+	}
+}
+
+func contains(d types.Disjunction, lit types.Literal) bool {
+	for _, l := range d {
+		if l == lit {
+			return true
+		}
+	}
+	return false
+}
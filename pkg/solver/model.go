@@ -21,6 +21,8 @@ type ModelElement struct {
 /*
 ModelList is the LinkedList implementation of the Model
 Insertion and Deletion is more effecient in Linkedlist so that is why it has been preferred over arrays
+
+The solver stores its Model in a Trail, ModelList is kept as a view of the Trail. Refer `trail.go`
 */
 type ModelList struct {
 	Head          *ModelElement // Head of the List
@@ -0,0 +1,108 @@
+package solver
+
+/*
+The optimize file minimizes a pseudo-Boolean objective over the models of the Formula by iterative
+strengthening: every model found of cost c adds the constraint that the objective is at most c-1,
+till the Formula becomes unsatisfiable and the last model is optimal.
+*/
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+// ObjectiveCost returns the sum of the coefficients of the literals of the objective which are true in the model
+func ObjectiveCost(objective []types.PBTerm, model []types.Literal) int64 {
+	var cost int64
+	for _, t := range objective {
+		if a := int(t.Literal.Atom()); a <= len(model) && model[a-1] == t.Literal {
+			cost += t.Coefficient
+		}
+	}
+	return cost
+}
+
+/*
+OptimizeContext searches for a model of the Formula with the least cost of the objective, refer ObjectiveCost.
+
+Every bound on the cost is added as a pseudo-Boolean constraint enabled by a new atom, which is assumed
+by the following searches only, so later calls of Solve are not restricted by the bounds. The function
+improved is called with the cost of every better model found and may be nil.
+
+The result is OPTIMUM with the best model in Model, UNSATISFIABLE if the Formula has no model, SATISFIABLE
+with the best model so far if the context or the limits stop the search, and UNKNOWN if that happens before
+the first model is found.
+*/
+func (solver *BaseCDCLSolver) OptimizeContext(ctx context.Context, objective []types.PBTerm, improved func(cost int64)) (types.Solution, int64, error) {
+	/*
+		Bounds hold without their atom, as the objective is never below 1-big. The coefficient big-cost
+		of the atom is at most 2*big, which must not overflow either.
+	*/
+	var (
+		big int64 = 1
+		ok        = true
+	)
+	for _, t := range objective {
+		switch {
+		case !ok:
+		case t.Coefficient == math.MinInt64:
+			ok = false
+		case t.Coefficient < 0:
+			big, ok = checkedAdd(big, -t.Coefficient)
+		default:
+			big, ok = checkedAdd(big, t.Coefficient)
+		}
+	}
+	if !ok || big > math.MaxInt64/2 {
+		return types.UNKNOWN, 0, handler.Throw("The coefficients of the objective overflow", nil)
+	}
+
+	solution, err := solver.SolveContext(ctx)
+	if err != nil || solution != types.SATISFIABLE {
+		return solution, 0, err
+	}
+
+	best := solver.Model()
+	cost := ObjectiveCost(objective, best)
+	for {
+		logger.Info(fmt.Sprintf("Found a model of cost %v", cost))
+		if improved != nil {
+			improved(cost)
+		}
+
+		// The objective is at most cost-1 if the bound atom is true: sum of -a*l >= 1-cost
+		atom := types.Literal(solver.AtomCount + 1)
+		bound := types.PBConstraint{
+			Terms:  []types.PBTerm{{Coefficient: big - cost, Literal: atom.Negate()}},
+			Degree: 1 - cost,
+		}
+		for _, t := range objective {
+			bound.Terms = append(bound.Terms, types.PBTerm{Coefficient: -t.Coefficient, Literal: t.Literal})
+		}
+		if err = solver.AddPB(bound); err != nil {
+			return types.SATISFIABLE, cost, err
+		}
+
+		solution, err = solver.SolveContext(ctx, atom)
+		switch {
+		case err != nil:
+			solution = types.SATISFIABLE
+		case solution == types.SATISFIABLE:
+			best = solver.Model()
+			cost = ObjectiveCost(objective, best)
+			continue
+		case solution == types.UNSATISFIABLE:
+			solution = types.OPTIMUM
+		default:
+			solution = types.SATISFIABLE
+		}
+
+		solver.model = best
+		return solution, cost, err
+	}
+}
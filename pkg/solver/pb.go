@@ -0,0 +1,351 @@
+package solver
+
+/*
+The pb file propagates linear pseudo-Boolean constraints natively instead of encoding them into clauses.
+
+A constraint sum of a_i*l_i >= k is normalized to positive coefficients, and its slack is the sum of the
+coefficients of the literals which are not refuted minus k. The constraint is violated once the slack is
+negative, and implies every unassigned literal whose coefficient is larger than the slack.
+*/
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+PBClause implements the Constraint interface for a normalized pseudo-Boolean constraint.
+
+The literals are sorted by decreasing coefficient, so the literals implied at any point are a prefix of
+the unassigned literals. Like the WatchedClause, the assignment is read from the formula.
+*/
+type PBClause struct {
+	literals     types.Disjunction     // Literals sorted by decreasing coefficient
+	coefficients []int64               // coefficients[i] is the positive coefficient of literals[i]
+	positions    map[types.Literal]int // positions[l] is the index of literal l
+	degree       int64                 // Sum of the coefficients of the true literals must be at least the degree
+	total        int64                 // Sum of all coefficients
+	slack        int64                 // Sum of the coefficients of the literals which are not refuted, minus the degree
+	formula      *WatchedFormula
+}
+
+// Adds two integers, the second result is false if the sum overflows
+func checkedAdd(a, b int64) (int64, bool) {
+	if b > 0 && a > math.MaxInt64-b || b < 0 && a < math.MinInt64-b {
+		return 0, false
+	}
+	return a + b, true
+}
+
+/*
+NormalizePB rewrites a constraint with positive coefficients on distinct atoms, using a*l = a - a*-l.
+Coefficients larger than the degree are lowered to the degree, which keeps the same models.
+A constraint with a degree of 0 or less always holds and has no terms.
+
+The sum of the normalized coefficients is returned along with the constraint, an error is returned if
+it or any intermediate sum overflows.
+*/
+func NormalizePB(c types.PBConstraint) (types.PBConstraint, int64, error) {
+	var (
+		atoms   []types.Atom
+		weights = make(map[types.Atom]int64) // Coefficient of the positive literal of every atom
+		degree  = c.Degree
+	)
+
+	add := func(a, b int64) (int64, error) {
+		sum, ok := checkedAdd(a, b)
+		if !ok {
+			return 0, handler.Throw(fmt.Sprintf("Pseudo-Boolean constraint %v overflows", c), nil)
+		}
+		return sum, nil
+	}
+
+	var err error
+	for _, t := range c.Terms {
+		if t.Literal == 0 {
+			return types.PBConstraint{}, 0, handler.Throw("Pseudo-Boolean constraint contains 0, which is not a literal", nil)
+		}
+		atom := t.Literal.Atom()
+		if _, ok := weights[atom]; !ok {
+			atoms = append(atoms, atom)
+		}
+		if t.Literal > 0 {
+			weights[atom], err = add(weights[atom], t.Coefficient)
+		} else {
+			// a*-x = a - a*x
+			weights[atom], err = add(weights[atom], -t.Coefficient)
+			if err == nil {
+				degree, err = add(degree, -t.Coefficient)
+			}
+		}
+		if err != nil {
+			return types.PBConstraint{}, 0, err
+		}
+	}
+
+	var normalized types.PBConstraint
+	for _, atom := range atoms {
+		switch w := weights[atom]; {
+		case w > 0:
+			normalized.Terms = append(normalized.Terms, types.PBTerm{Coefficient: w, Literal: types.Literal(atom)})
+		case w < 0:
+			// -a*x = a*-x - a
+			normalized.Terms = append(normalized.Terms, types.PBTerm{Coefficient: -w, Literal: types.Literal(atom).Negate()})
+			if degree, err = add(degree, -w); err != nil {
+				return types.PBConstraint{}, 0, err
+			}
+		}
+	}
+
+	if degree <= 0 {
+		return types.PBConstraint{}, 0, nil
+	}
+	normalized.Degree = degree
+	var total int64
+	for i, t := range normalized.Terms {
+		if t.Coefficient > degree {
+			normalized.Terms[i].Coefficient = degree
+		}
+		if total, err = add(total, normalized.Terms[i].Coefficient); err != nil {
+			return types.PBConstraint{}, 0, err
+		}
+	}
+	sort.SliceStable(normalized.Terms, func(i, j int) bool {
+		return normalized.Terms[i].Coefficient > normalized.Terms[j].Coefficient
+	})
+
+	return normalized, total, nil
+}
+
+/*
+Constructs a PBClause from a constraint and the sum of its coefficients, as returned by NormalizePB.
+It is attached to a formula by Constrain.
+*/
+func ConstructPBClause(c types.PBConstraint, total int64) *PBClause {
+	pb := &PBClause{
+		positions: make(map[types.Literal]int, len(c.Terms)),
+		degree:    c.Degree,
+		total:     total,
+	}
+	for i, t := range c.Terms {
+		pb.literals = append(pb.literals, t.Literal)
+		pb.coefficients = append(pb.coefficients, t.Coefficient)
+		pb.positions[t.Literal] = i
+	}
+	return pb
+}
+
+// Sums the coefficients of the true literals and of the literals which are not refuted
+func (c *PBClause) sums() (assigned int64, possible int64) {
+	for i, l := range c.literals {
+		switch c.formula.state(l) {
+		case ASSIGNED:
+			assigned += c.coefficients[i]
+			possible += c.coefficients[i]
+		case UNASSIGNED:
+			possible += c.coefficients[i]
+		}
+	}
+	return assigned, possible
+}
+
+func (c *PBClause) Type() types.ClauseType {
+	assigned, possible := c.sums()
+	slack := possible - c.degree
+	switch {
+	case assigned >= c.degree:
+		return types.SOLVED_CLAUSE
+	case slack < 0:
+		return types.EMPTY_CLAUSE
+	}
+	for i, l := range c.literals {
+		if c.coefficients[i] <= slack {
+			break
+		}
+		if c.formula.state(l) == UNASSIGNED {
+			return types.UNIT_CLAUSE
+		}
+	}
+	return types.DECISION_CLAUSE
+}
+
+// Assignments are tracked by the WatchedFormula, hence Apply does not change the constraint
+func (c *PBClause) Apply(l types.Literal) types.Clause {
+	return c
+}
+
+// Assignments are tracked by the WatchedFormula, hence Undo does not change the constraint
+func (c *PBClause) Undo(l types.Literal) types.Clause {
+	return c
+}
+
+// Recomputes the slack from the current assignment of the formula
+func (c *PBClause) Reset() types.Clause {
+	_, possible := c.sums()
+	c.slack = possible - c.degree
+	return c
+}
+
+func (c *PBClause) Contains(l types.Literal) bool {
+	_, ok := c.positions[l]
+	return ok && c.formula.state(l) != REFUTED
+}
+
+func (c *PBClause) IsSolved() bool {
+	assigned, _ := c.sums()
+	return assigned >= c.degree
+}
+
+func (c *PBClause) IsLearnt() bool {
+	return false
+}
+
+/*
+Returns the literals of the constraint which are not refuted, the unassigned literals first.
+The first literal is implied if the constraint is unit.
+*/
+func (c *PBClause) Disjunction() types.Disjunction {
+	var unassigned, assigned types.Disjunction
+	for _, l := range c.literals {
+		switch c.formula.state(l) {
+		case UNASSIGNED:
+			unassigned = append(unassigned, l)
+		case ASSIGNED:
+			assigned = append(assigned, l)
+		}
+	}
+	return append(unassigned, assigned...)
+}
+
+// Returns the literals of the constraint, sorted by decreasing coefficient
+func (c *PBClause) Original() types.Disjunction {
+	return c.literals
+}
+
+// Returns the normalized constraint
+func (c *PBClause) Constraint() types.PBConstraint {
+	pb := types.PBConstraint{Degree: c.degree}
+	for i, l := range c.literals {
+		pb.Terms = append(pb.Terms, types.PBTerm{Coefficient: c.coefficients[i], Literal: l})
+	}
+	return pb
+}
+
+func (c *PBClause) Attach(f *WatchedFormula) {
+	c.formula = f
+}
+
+// The constraint is told when one of its literals is refuted
+func (c *PBClause) Triggers() []types.Literal {
+	triggers := make([]types.Literal, len(c.literals))
+	for i, l := range c.literals {
+		triggers[i] = l.Negate()
+	}
+	return triggers
+}
+
+func (c *PBClause) Assign(l types.Literal) {
+	c.slack -= c.coefficients[c.positions[l.Negate()]]
+	c.Check()
+}
+
+func (c *PBClause) Unassign(l types.Literal) {
+	c.slack += c.coefficients[c.positions[l.Negate()]]
+}
+
+// Explains a conflict if the slack is negative, otherwise every unassigned literal with a coefficient above the slack
+func (c *PBClause) Check() {
+	if c.slack < 0 {
+		c.formula.Explain(c.explain(0, 0))
+		return
+	}
+
+	for i, l := range c.literals {
+		if c.coefficients[i] <= c.slack {
+			break
+		}
+		if c.formula.state(l) == UNASSIGNED {
+			c.formula.Explain(c.explain(l, c.coefficients[i]))
+		}
+	}
+}
+
+/*
+Returns a clause implied by the constraint which is unit on the given literal, or empty for a conflict
+if the literal is 0. Refuted literals are taken by decreasing coefficient till their coefficients and the
+coefficient of the implied literal exceed the slack of the constraint without any assignment.
+*/
+func (c *PBClause) explain(implied types.Literal, coefficient int64) types.Disjunction {
+	var (
+		d      types.Disjunction
+		needed = c.total - c.degree - coefficient
+		sum    int64
+	)
+	if implied != 0 {
+		d = append(d, implied)
+	}
+	for i, l := range c.literals {
+		if sum > needed {
+			break
+		}
+		if c.formula.state(l) == REFUTED {
+			d = append(d, l)
+			sum += c.coefficients[i]
+		}
+	}
+	return d
+}
+
+/*
+AddPB adds a pseudo-Boolean constraint to the Formula, it is kept for all later calls of Solve.
+
+The constraint is normalized with NormalizePB. Constraints which are clauses, because their degree
+is 1, are added with AddClause, other constraints need a Formula which is a Constrainer. Proofs do not
+cover pseudo-Boolean constraints, hence they cannot be added while a proof is written.
+*/
+func (solver *BaseCDCLSolver) AddPB(c types.PBConstraint) error {
+	pb, total, err := NormalizePB(c)
+	if err != nil {
+		return err
+	}
+	if pb.Degree == 0 {
+		// The constraint always holds
+		return nil
+	}
+
+	var clause types.Disjunction
+	for _, t := range pb.Terms {
+		clause = append(clause, t.Literal)
+	}
+	if total < pb.Degree {
+		// The constraint never holds
+		return solver.AddClause(nil)
+	}
+	if pb.Terms[len(pb.Terms)-1].Coefficient == pb.Degree {
+		// Any true literal satisfies the constraint
+		return solver.AddClause(clause)
+	}
+
+	if solver.Proof != nil {
+		return handler.Throw("Proofs are not available with pseudo-Boolean constraints", nil)
+	}
+	f, ok := solver.F.(Constrainer)
+	if !ok {
+		return handler.Throw("The formula does not support pseudo-Boolean constraints", nil)
+	}
+
+	for _, l := range clause {
+		solver.Extend(uint(l.Atom()))
+	}
+	solver.Backjump(0)
+	f.Constrain(ConstructPBClause(pb, total))
+
+	logger.Info(fmt.Sprintf("Added pseudo-Boolean constraint %v", pb))
+
+	return nil
+}
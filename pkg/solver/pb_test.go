@@ -0,0 +1,140 @@
+package solver_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func randomPB(random *rand.Rand, atoms int) types.PBConstraint {
+	var c types.PBConstraint
+	for i := 1 + random.Intn(5); i > 0; i-- {
+		l := types.Literal(1 + random.Intn(atoms))
+		if random.Intn(2) == 0 {
+			l = l.Negate()
+		}
+		c.Terms = append(c.Terms, types.PBTerm{Coefficient: int64(random.Intn(9) - 3), Literal: l})
+	}
+	c.Degree = int64(random.Intn(7) - 1)
+	return c
+}
+
+// Returns the least cost of a model of the constraints found by trying every assignment, false if there is none
+func bruteForcePB(atoms int, constraints []types.PBConstraint, objective []types.PBTerm) (int64, bool) {
+	var (
+		best  int64
+		found bool
+	)
+	model := make([]types.Literal, atoms)
+	for bits := 0; bits < 1<<atoms; bits++ {
+		for i := range model {
+			model[i] = types.Literal(i + 1)
+			if bits&(1<<i) == 0 {
+				model[i] = model[i].Negate()
+			}
+		}
+		if len(solver.VerifyPB(constraints, model)) > 0 {
+			continue
+		}
+		if cost := solver.ObjectiveCost(objective, model); !found || cost < best {
+			best, found = cost, true
+		}
+	}
+	return best, found
+}
+
+func TestNormalizePB(t *testing.T) {
+	// 3x1 - 2x2 + x1 + 5 -x3 >= 2 is 4x1 + 2 -x2 + 5 -x3 >= 4 after normalization, with 5 lowered to 4
+	pb, total, err := solver.NormalizePB(types.PBConstraint{
+		Terms:  []types.PBTerm{{Coefficient: 3, Literal: 1}, {Coefficient: -2, Literal: 2}, {Coefficient: 1, Literal: 1}, {Coefficient: 5, Literal: -3}},
+		Degree: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pb.Degree != 4 || total != 10 || len(pb.Terms) != 3 || pb.Terms[0] != (types.PBTerm{Coefficient: 4, Literal: 1}) ||
+		pb.Terms[1] != (types.PBTerm{Coefficient: 4, Literal: -3}) || pb.Terms[2] != (types.PBTerm{Coefficient: 2, Literal: -2}) {
+		t.Errorf("Unexpected normalized constraint %+v", pb)
+	}
+
+	if pb, _, err = solver.NormalizePB(types.PBConstraint{Terms: []types.PBTerm{{Coefficient: 1, Literal: 1}}, Degree: 0}); err != nil || pb.Degree != 0 || len(pb.Terms) != 0 {
+		t.Errorf("Expected a trivial constraint, found %+v %v", pb, err)
+	}
+}
+
+func TestPBOverflow(t *testing.T) {
+	// Every coefficient fits below the degree, but their sum does not fit an int64
+	const large = 1 << 62
+	c := types.PBConstraint{
+		Terms:  []types.PBTerm{{Coefficient: large, Literal: 1}, {Coefficient: large, Literal: 2}, {Coefficient: large, Literal: 3}},
+		Degree: 9000000000000000000,
+	}
+	if _, _, err := solver.NormalizePB(c); err == nil {
+		t.Errorf("Expected the constraint %v to overflow", c)
+	}
+
+	s, err := solver.InitializeBaseSolver(types.SATFile{AtomCount: 3}, solver.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.AddPB(c); err == nil {
+		t.Errorf("Expected the constraint %v to overflow", c)
+	}
+	if solution, _, err := s.OptimizeContext(context.Background(), c.Terms, nil); err == nil {
+		t.Errorf("Expected the objective %v to overflow, found %v", c.Terms, solution)
+	}
+}
+
+func TestPB(t *testing.T) {
+	random := rand.New(rand.NewSource(11))
+
+	for n := 0; n < 300; n++ {
+		atoms := 2 + random.Intn(7)
+		var constraints []types.PBConstraint
+		for i := random.Intn(2 * atoms); i >= 0; i-- {
+			constraints = append(constraints, randomPB(random, atoms))
+		}
+		objective := randomPB(random, atoms).Terms
+
+		s, err := solver.InitializeBaseSolver(types.SATFile{AtomCount: uint(atoms)}, solver.Options{Reduce: 20, Restart: solver.LUBY_RESTART, RestartBase: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range constraints {
+			if err = s.AddPB(c); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		best, found := bruteForcePB(atoms, constraints, objective)
+		solution, err := s.Solve()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found != (solution == types.SATISFIABLE) {
+			t.Fatalf("%+v: expected satisfiable %v, found %v", constraints, found, solution)
+		}
+		if !found {
+			continue
+		}
+		if violated := solver.VerifyPB(constraints, s.Model()); len(violated) > 0 {
+			t.Fatalf("%+v: model %v violates constraint %v", constraints, s.Model(), violated[0])
+		}
+
+		solution, cost, err := s.OptimizeContext(context.Background(), objective, nil)
+		if err != nil || solution != types.OPTIMUM || cost != best {
+			t.Fatalf("%+v min %+v: expected the optimum %v, found %v %v %v", constraints, objective, best, solution, cost, err)
+		}
+		if solver.ObjectiveCost(objective, s.Model()) != best || len(solver.VerifyPB(constraints, s.Model())) > 0 {
+			t.Errorf("The optimal model %v does not cost %v or violates a constraint", s.Model(), best)
+		}
+
+		// The bounds of the optimization do not restrict later calls
+		if solution, _ = s.Solve(); solution != types.SATISFIABLE {
+			t.Errorf("Expected the constraints to stay satisfiable, found %v", solution)
+		}
+	}
+}
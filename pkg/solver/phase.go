@@ -0,0 +1,213 @@
+package solver
+
+/*
+The phase file contains the polarity selection used by the solver to pick the sign of decision literals.
+*/
+
+import (
+	"math"
+	"math/rand"
+
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+PolarityType is an enum defining the default polarity of decision literals,
+used when no saved or target phase is available for an atom.
+*/
+type PolarityType uint
+
+const (
+	FALSE_POLARITY         PolarityType = iota // Decide atoms as false
+	TRUE_POLARITY                              // Decide atoms as true
+	RANDOM_POLARITY                            // Decide atoms with a random sign
+	JEROSLOW_WANG_POLARITY                     // Decide atoms with the sign preferred by the Jeroslow-Wang score
+)
+
+/*
+RephaseType is an enum defining the phases the saved phases are reset to on rephasing
+*/
+type RephaseType uint
+
+const (
+	ORIGINAL_REPHASE RephaseType = iota // Reset saved phases to the default polarity
+	BEST_REPHASE                        // Reset saved phases to the best phase
+	INVERTED_REPHASE                    // Reset saved phases to the inverted default polarity
+)
+
+// Order in which the saved phases are reset on rephasing
+var rephaseCycle = []RephaseType{BEST_REPHASE, ORIGINAL_REPHASE, BEST_REPHASE, INVERTED_REPHASE}
+
+/*
+Phase selects the polarity of decision literals.
+
+With phase saving, the last value of every atom removed from the Trail is remembered and reused
+when the atom is decided again. The target phase is the assignment of the largest conflict free
+Trail since the last rephase, and the best phase is the largest such assignment overall.
+*/
+type Phase struct {
+	Polarity    PolarityType    // Default polarity of decision literals
+	Saving      bool            // Reuse the last value of an atom when deciding it
+	TargetPhase bool            // Prefer the target phase over the saved phase when deciding
+	Initial     []types.Literal // Initial[a] is the default literal of atom a
+	Saved       []types.Literal // Saved[a] is the last literal of atom a removed from the Trail, 0 if none
+	Target      []types.Literal // Target[a] is the literal of atom a in the target phase, 0 if none
+	Best        []types.Literal // Best[a] is the literal of atom a in the best phase, 0 if none
+	targetSize  int             // Size of the Trail the target phase was taken from
+	bestSize    int             // Size of the Trail the best phase was taken from
+	interval    uint            // No of conflicts between rephasing, 0 disables rephasing
+	conflicts   uint            // No of conflicts seen so far
+	next        uint            // No of conflicts at which to rephase next
+	rephases    uint            // No of times rephasing has been done
+	random      *rand.Rand
+}
+
+// Constructs a Phase for the clauses of the SATFile
+func ConstructPhase(satfile types.SATFile, options Options) *Phase {
+	p := &Phase{
+		Polarity:    options.Polarity,
+		Saving:      options.PhaseSaving,
+		TargetPhase: options.TargetPhase,
+		Initial:     make([]types.Literal, satfile.AtomCount+1),
+		Saved:       make([]types.Literal, satfile.AtomCount+1),
+		Target:      make([]types.Literal, satfile.AtomCount+1),
+		Best:        make([]types.Literal, satfile.AtomCount+1),
+		interval:    options.Rephase,
+		next:        options.Rephase,
+		random:      rand.New(rand.NewSource(options.Seed)),
+	}
+
+	for a := range p.Initial {
+		p.Initial[a] = types.Literal(a)
+		if p.Polarity == FALSE_POLARITY {
+			p.Initial[a] = p.Initial[a].Negate()
+		}
+	}
+
+	if p.Polarity == JEROSLOW_WANG_POLARITY {
+		p.jeroslowWang(satfile.Clauses)
+	}
+
+	return p
+}
+
+/*
+Sets the default literal of every atom to the sign with the larger Jeroslow-Wang score.
+
+The score of a literal is the sum of 2^-|C| over every clause C containing the literal.
+*/
+func (p *Phase) jeroslowWang(clauses []types.Disjunction) {
+	score := make(map[types.Literal]float64)
+	for _, d := range clauses {
+		w := math.Pow(2, -float64(len(d)))
+		for _, l := range d {
+			score[l] += w
+		}
+	}
+
+	for a := 1; a < len(p.Initial); a++ {
+		lit := types.Literal(a)
+		if score[lit.Negate()] >= score[lit] {
+			lit = lit.Negate()
+		}
+		p.Initial[a] = lit
+	}
+}
+
+/*
+Extends the phases to hold the given number of atoms.
+New atoms take the default polarity, with no clauses to score them Jeroslow-Wang prefers false.
+*/
+func (p *Phase) Extend(atomCount uint) {
+	for a := len(p.Initial); a <= int(atomCount); a++ {
+		lit := types.Literal(a)
+		if p.Polarity == FALSE_POLARITY || p.Polarity == JEROSLOW_WANG_POLARITY {
+			lit = lit.Negate()
+		}
+		p.Initial = append(p.Initial, lit)
+		p.Saved = append(p.Saved, 0)
+		p.Target = append(p.Target, 0)
+		p.Best = append(p.Best, 0)
+	}
+}
+
+// Returns the literal the atom should be decided as
+func (p *Phase) Select(a types.Atom) types.Literal {
+	if p.TargetPhase && p.Target[a] != 0 {
+		return p.Target[a]
+	}
+	if p.Saving && p.Saved[a] != 0 {
+		return p.Saved[a]
+	}
+	if p.Polarity == RANDOM_POLARITY && p.random.Intn(2) == 0 {
+		return p.Initial[a].Negate()
+	}
+	return p.Initial[a]
+}
+
+// Saves the literal of an atom removed from the Trail
+func (p *Phase) Save(l types.Literal) {
+	p.Saved[l.Atom()] = l
+}
+
+/*
+Conflict updates the target and best phases with the conflict free part of the Trail,
+which is every level below the level of the conflict, and rephases when it is due.
+*/
+func (p *Phase) Conflict(trail *Trail) {
+	size := len(trail.Literals)
+	if level := trail.DecisionLevel(); level > 0 {
+		size = trail.Decisions[level-1]
+	}
+
+	if size > p.targetSize {
+		copyPhase(p.Target, trail.Literals[:size])
+		p.targetSize = size
+	}
+	if size > p.bestSize {
+		copyPhase(p.Best, trail.Literals[:size])
+		p.bestSize = size
+	}
+
+	p.conflicts++
+	if p.interval > 0 && p.conflicts >= p.next {
+		p.Rephase()
+	}
+}
+
+// Copies the literals into the phase
+func copyPhase(phase []types.Literal, literals []types.Literal) {
+	for _, l := range literals {
+		phase[l.Atom()] = l
+	}
+}
+
+/*
+Rephase resets the saved phases following the rephase cycle and clears the target phase.
+The interval between two rephases grows arithmetically.
+*/
+func (p *Phase) Rephase() {
+	switch rephaseCycle[p.rephases%uint(len(rephaseCycle))] {
+	case BEST_REPHASE:
+		for a, l := range p.Best {
+			if l != 0 {
+				p.Saved[a] = l
+			}
+		}
+		p.bestSize = 0
+	case ORIGINAL_REPHASE:
+		copy(p.Saved, p.Initial)
+	case INVERTED_REPHASE:
+		for a, l := range p.Initial {
+			p.Saved[a] = l.Negate()
+		}
+	}
+
+	for a := range p.Target {
+		p.Target[a] = 0
+	}
+	p.targetSize = 0
+
+	p.rephases++
+	p.next = p.conflicts + p.interval*(p.rephases+1)
+}
@@ -0,0 +1,73 @@
+package solver_test
+
+import (
+	"testing"
+
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestJeroslowWang(t *testing.T) {
+	sat := types.SATFile{
+		AtomCount: 3,
+		Clauses: []types.Disjunction{
+			{1, 2},
+			{1, -2},
+			{-1, -2, 3},
+			{-2},
+		},
+	}
+	phase := solver.ConstructPhase(sat, solver.Options{Polarity: solver.JEROSLOW_WANG_POLARITY})
+
+	if l := phase.Select(1); l != 1 {
+		t.Errorf("Expected 1 to be selected, found %v", l)
+	}
+	if l := phase.Select(2); l != -2 {
+		t.Errorf("Expected -2 to be selected, found %v", l)
+	}
+	if l := phase.Select(3); l != 3 {
+		t.Errorf("Expected 3 to be selected, found %v", l)
+	}
+}
+
+func TestPhaseSaving(t *testing.T) {
+	sat := types.SATFile{AtomCount: 4}
+	phase := solver.ConstructPhase(sat, solver.Options{
+		Polarity:    solver.FALSE_POLARITY,
+		PhaseSaving: true,
+		TargetPhase: true,
+	})
+
+	trail := solver.ConstructTrail(4)
+	trail.Pushback(solver.ModelElement{Literal: 1, Decision: true})
+	trail.Pushback(solver.ModelElement{Literal: 2})
+	trail.Pushback(solver.ModelElement{Literal: 3, Decision: true})
+
+	// Only the first level is free of conflicts, hence the target phase is [1 2]
+	phase.Conflict(&trail)
+	for _, l := range trail.Backjump(0) {
+		phase.Save(l)
+	}
+
+	if l := phase.Select(2); l != 2 {
+		t.Errorf("Expected target phase 2 to be selected, found %v", l)
+	}
+	if l := phase.Select(3); l != 3 {
+		t.Errorf("Expected saved phase 3 to be selected, found %v", l)
+	}
+	if l := phase.Select(4); l != -4 {
+		t.Errorf("Expected default phase -4 to be selected, found %v", l)
+	}
+
+	// The inverted rephase sets the saved phases to the negation of the default phases
+	phase.Rephase()
+	phase.Rephase()
+	phase.Rephase()
+	phase.Rephase()
+	if l := phase.Select(3); l != 3 {
+		t.Errorf("Expected inverted phase 3 to be selected, found %v", l)
+	}
+	if l := phase.Select(1); l != 1 {
+		t.Errorf("Expected inverted phase 1 to be selected, found %v", l)
+	}
+}
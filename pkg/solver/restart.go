@@ -0,0 +1,176 @@
+package solver
+
+/*
+The restart file contains the restart policies used by the solver to decide when to restart the search.
+*/
+
+/*
+Restarter decides when the solver should restart.
+
+The solver informs the Restarter of the LBD of every learnt clause, and asks it whether
+a restart is due before making a decision.
+*/
+type Restarter interface {
+	Conflict(lbd uint) // Called after every conflict with the LBD of the learnt clause
+	Due() bool         // Returns true if the solver should restart
+	Restart()          // Called after the solver has restarted
+}
+
+/*
+RestartType is an enum defining the implementations of the Restarter interface
+that the solver can be initialized with.
+*/
+type RestartType uint
+
+const (
+	NO_RESTART        RestartType = iota // Never restart
+	LUBY_RESTART                         // Restart intervals follow the Luby sequence
+	GEOMETRIC_RESTART                    // Restart intervals grow geometrically
+	GLUCOSE_RESTART                      // Restart when recent LBDs are worse than the average LBD
+)
+
+// NoRestart implements the Restarter interface for a solver that never restarts
+type NoRestart struct{}
+
+func (r NoRestart) Conflict(lbd uint) {}
+
+func (r NoRestart) Due() bool { return false }
+
+func (r NoRestart) Restart() {}
+
+/*
+LubyRestart implements the Restarter interface using the Luby sequence.
+
+The i-th restart happens after unit * luby(i) conflicts, where luby is 1 1 2 1 1 2 4 1 1 2 ...
+*/
+type LubyRestart struct {
+	unit      uint // No of conflicts in a unit of the sequence
+	index     uint // Index of the current interval in the Luby sequence
+	limit     uint // No of conflicts after which the next restart is due
+	conflicts uint // No of conflicts since the last restart
+}
+
+// Constructs a LubyRestart with the given unit
+func ConstructLubyRestart(unit uint) *LubyRestart {
+	return &LubyRestart{
+		unit:  unit,
+		index: 1,
+		limit: unit,
+	}
+}
+
+// Returns the i-th element of the Luby sequence, counting from 1
+func Luby(i uint) uint {
+	// Find the finite subsequence of size 2^k - 1 that contains i
+	k := uint(1)
+	for (uint(1)<<k)-1 < i {
+		k++
+	}
+	for (uint(1)<<k)-1 != i {
+		i -= (uint(1) << (k - 1)) - 1
+		k = 1
+		for (uint(1)<<k)-1 < i {
+			k++
+		}
+	}
+	return uint(1) << (k - 1)
+}
+
+func (r *LubyRestart) Conflict(lbd uint) {
+	r.conflicts++
+}
+
+func (r *LubyRestart) Due() bool {
+	return r.conflicts >= r.limit
+}
+
+func (r *LubyRestart) Restart() {
+	r.index++
+	r.limit = r.unit * Luby(r.index)
+	r.conflicts = 0
+}
+
+// GeometricRestart implements the Restarter interface with intervals growing by a constant factor
+type GeometricRestart struct {
+	limit     float64 // No of conflicts after which the next restart is due
+	factor    float64 // Factor by which the interval grows after every restart
+	conflicts uint    // No of conflicts since the last restart
+}
+
+// Constructs a GeometricRestart with the given first interval and factor
+func ConstructGeometricRestart(interval uint, factor float64) *GeometricRestart {
+	return &GeometricRestart{
+		limit:  float64(interval),
+		factor: factor,
+	}
+}
+
+func (r *GeometricRestart) Conflict(lbd uint) {
+	r.conflicts++
+}
+
+func (r *GeometricRestart) Due() bool {
+	return float64(r.conflicts) >= r.limit
+}
+
+func (r *GeometricRestart) Restart() {
+	r.limit *= r.factor
+	r.conflicts = 0
+}
+
+// movingAverage is an exponential moving average with bias correction for the first values
+type movingAverage struct {
+	Value  float64 // Bias corrected average
+	biased float64
+	alpha  float64 // Weight of a new value
+	beta   float64 // (1 - alpha)^n after n updates
+}
+
+func newMovingAverage(alpha float64) movingAverage {
+	return movingAverage{alpha: alpha, beta: 1}
+}
+
+func (m *movingAverage) update(x float64) {
+	m.biased += m.alpha * (x - m.biased)
+	m.beta *= 1 - m.alpha
+	m.Value = m.biased / (1 - m.beta)
+}
+
+/*
+GlucoseRestart implements the Restarter interface using moving averages of LBDs as done in Glucose.
+
+A fast moving average follows the LBD of recent learnt clauses and a slow moving average follows
+the LBD over the whole search. A restart is due when the recent clauses are worse than the
+overall average by a margin.
+*/
+type GlucoseRestart struct {
+	Fast      movingAverage
+	Slow      movingAverage
+	margin    float64 // Restart when Fast > margin * Slow
+	minimum   uint    // Minimum no of conflicts between restarts
+	conflicts uint    // No of conflicts since the last restart
+}
+
+// Constructs a GlucoseRestart with the default parameters
+func ConstructGlucoseRestart() *GlucoseRestart {
+	return &GlucoseRestart{
+		Fast:    newMovingAverage(0.03),
+		Slow:    newMovingAverage(1e-5),
+		margin:  1.1,
+		minimum: 2,
+	}
+}
+
+func (r *GlucoseRestart) Conflict(lbd uint) {
+	r.conflicts++
+	r.Fast.update(float64(lbd))
+	r.Slow.update(float64(lbd))
+}
+
+func (r *GlucoseRestart) Due() bool {
+	return r.conflicts >= r.minimum && r.Fast.Value > r.margin*r.Slow.Value
+}
+
+func (r *GlucoseRestart) Restart() {
+	r.conflicts = 0
+}
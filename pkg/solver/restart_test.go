@@ -0,0 +1,85 @@
+package solver_test
+
+import (
+	"testing"
+
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestLuby(t *testing.T) {
+	expected := []uint{1, 1, 2, 1, 1, 2, 4, 1, 1, 2, 1, 1, 2, 4, 8, 1}
+	for i, e := range expected {
+		if l := solver.Luby(uint(i + 1)); l != e {
+			t.Errorf("Luby(%v) should be %v, found %v", i+1, e, l)
+		}
+	}
+}
+
+func TestLubyRestart(t *testing.T) {
+	r := solver.ConstructLubyRestart(2)
+
+	for i, e := range []uint{2, 2, 4} {
+		for c := uint(0); c < e; c++ {
+			if r.Due() {
+				t.Errorf("Restart %v due after %v conflicts, expected %v", i, c, e)
+			}
+			r.Conflict(1)
+		}
+		if !r.Due() {
+			t.Errorf("Restart %v not due after %v conflicts", i, e)
+		}
+		r.Restart()
+	}
+}
+
+func TestGlucoseRestart(t *testing.T) {
+	r := solver.ConstructGlucoseRestart()
+
+	for i := 0; i < 100; i++ {
+		r.Conflict(3)
+	}
+	if r.Due() {
+		t.Errorf("Restart should not be due for constant LBD")
+	}
+
+	for i := 0; i < 10; i++ {
+		r.Conflict(10)
+	}
+	if !r.Due() {
+		t.Errorf("Restart should be due after a series of bad LBDs")
+	}
+}
+
+func TestSolverRestart(t *testing.T) {
+	sat := types.SATFile{
+		AtomCount: 4,
+		Clauses: []types.Disjunction{
+			{1},
+			{-1, 2},
+			{-3, 4},
+		},
+	}
+	s, err := solver.InitializeBaseSolver(sat, solver.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for c := s.F.NextClause(); c.Type() == types.UNIT_CLAUSE; c = s.F.NextClause() {
+		if err = s.UnitPropagate(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = s.Decide(); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Restart()
+
+	if s.Trail.DecisionLevel() != 0 || s.Trail.Size() != 2 {
+		t.Errorf("Expected the level 0 literals [1 2] after restart, found %v", s.Trail.Literals)
+	}
+	if c := s.F.NextClause(); c.Type() != types.DECISION_CLAUSE {
+		t.Errorf("Expected decision clause after restart, found %v", c.Disjunction())
+	}
+}
@@ -4,10 +4,14 @@ The solver package contains the BaseCDCLSolver
 package solver
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"time"
 
 	handler "github.com/alanpjohn/go-cdcl/pkg/error"
 	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+	proof "github.com/alanpjohn/go-cdcl/pkg/proof"
 	types "github.com/alanpjohn/go-cdcl/pkg/types"
 )
 
@@ -15,11 +19,46 @@ import (
 The BaseCDCLSolver is a close implementation of SAT Solver v3 from http://poincare.matf.bg.ac.rs/~filip/phd/sat-tutorial.pdf
 */
 type BaseCDCLSolver struct {
-	Model         ModelList       // Model is stored in a customized LinkedList. Refer `model.go`
-	Check         []*ModelElement // Check is used to check is an Atom has been included in the Model
-	AtomCount     uint            // No of Atoms
-	DecisionCount uint            // No of decisions made
-	F             types.Formula   // Formula of clause to solve satisfiability problem
+	Trail     Trail         // Model is stored in an array based Trail. Refer `trail.go`
+	AtomCount uint          // No of Atoms
+	F         types.Formula // Formula of clause to solve satisfiability problem
+	Brancher  Brancher      // Decision heuristic used to select the next atom. Refer `brancher.go`
+	Phase     *Phase        // Polarity selection for decision literals. Refer `phase.go`
+	Restarter Restarter     // Restart policy of the solver. Refer `restart.go`
+	DB        *ClauseDB     // Metadata of learnt clauses, nil if the Formula cannot forget clauses. Refer `clausedb.go`
+
+	/*
+		Proof records every learnt and deleted clause as a DRAT proof, nil if proofs are disabled.
+
+		The proof is relative to the clauses the solver was initialized with, clauses added with
+		AddClause are not part of it.
+	*/
+	Proof *proof.DRATWriter
+
+	MinimizeRecursive bool    // Remove literals implied by the rest of the learnt clause. Refer `minimize.go`
+	MinimizeBinary    bool    // Remove literals self-subsumed by binary clauses with the asserting literal
+	marks             []uint8 // Marks on atoms used while minimizing learnt clauses
+
+	Assumptions  []types.Literal // Assumptions of the current call to Solve, decided first. Refer `incremental.go`
+	failed       []types.Literal // Assumptions responsible for the last UNSATISFIABLE result
+	inconsistent bool            // Set once the Formula is found to be unsatisfiable without assumptions
+	model        []types.Literal // Assignment found by the last SATISFIABLE result, nil otherwise
+
+	selectorBase  uint  // Atom before the selector atom of the first clause of the SATFile. Refer `core.go`
+	selectorCount uint  // No of clauses with a selector atom, 0 if cores are disabled
+	core          []int // Indices of the clauses of the SATFile in the last unsatisfiable core
+
+	ConflictLimit uint       // No of conflicts after which a call of Solve stops, 0 for no limit. Refer `limit.go`
+	DecisionLimit uint       // No of decisions after which a call of Solve stops, 0 for no limit
+	stopped       StopReason // Why the last call of Solve returned UNKNOWN
+
+	Stats            types.Stats   // Counters of all calls of Solve, Statistics adds the time and memory. Refer `stats.go`
+	Progress         io.Writer     // Destination of the progress lines, nil disables them
+	ProgressInterval time.Duration // Minimum time between progress lines
+	elapsed          time.Duration // Time spent in the calls of Solve which returned
+	started          time.Time     // Start of the current call of Solve, zero outside of Solve
+	reported         time.Time     // Time of the last progress line
+	reports          uint          // No of progress lines written
 
 	/*
 		Construct wraps Disjunctions into Clauses.
@@ -29,11 +68,63 @@ type BaseCDCLSolver struct {
 	Construct func(types.Disjunction, bool) types.Clause // a Clause Construction function which is overwritten on initialization to support modularity
 }
 
+/*
+FormulaType is an enum defining the implementations of the Formula interface
+that the solver can be initialized with.
+*/
+type FormulaType uint
+
+const (
+	WATCHED_FORMULA FormulaType = iota // Two watched literal formula. Refer `watched.go`
+	BASE_FORMULA                       // Formula which updates every clause on each assignment. Refer `formula.go`
+)
+
+// Options contains the configurable parameters of the BaseCDCLSolver
+type Options struct {
+	Experimental bool          // Use experimental Clause implementations. Refer `experimental.go`
+	Formula      FormulaType   // Implementation of Formula used by the solver
+	Brancher     BrancherType  // Decision heuristic used by the solver
+	Decay        float64       // Activity decay factor of VSIDS, defaults to 0.95
+	Seed         int64         // Seed for the random number generators of the solver
+	Polarity     PolarityType  // Default polarity of decision literals
+	PhaseSaving  bool          // Reuse the last value of an atom when deciding it
+	TargetPhase  bool          // Prefer the target phase over the saved phase when deciding
+	Rephase      uint          // No of conflicts between resetting the saved phases, 0 disables rephasing
+	Restart      RestartType   // Restart policy of the solver
+	RestartBase  uint          // No of conflicts before the first luby or geometric restart, defaults to 100
+	RestartGrow  float64       // Factor by which geometric restart intervals grow, defaults to 1.5
+	Reduce       uint          // No of conflicts before the first learnt clause reduction, 0 disables reduction
+	Minimize     bool          // Recursively minimize learnt clauses
+	Binary       bool          // Minimize learnt clauses with binary clauses
+	Proof        io.Writer     // Destination of the DRAT proof, nil disables proofs
+	BinaryProof  bool          // Write the DRAT proof in the binary encoding
+	Core         bool          // Add selector atoms to the clauses to find unsatisfiable cores. Refer `core.go`
+	Conflicts    uint          // Conflict limit of every call of Solve, 0 for no limit
+	Decisions    uint          // Decision limit of every call of Solve, 0 for no limit
+	Progress     io.Writer     // Destination of progress lines, nil disables them
+	Interval     time.Duration // Minimum time between progress lines, defaults to 1s
+}
+
 // Intializes all the BaseCDCLSolver fields based on SATFile and CLI Flags
-func InitializeBaseSolver(satfile types.SATFile, experimental bool) (solver BaseCDCLSolver, err error) {
+func InitializeBaseSolver(satfile types.SATFile, options Options) (solver BaseCDCLSolver, err error) {
 	var clauses []types.Clause
 
-	if experimental {
+	if options.Core {
+		if options.Proof != nil {
+			return solver, handler.Throw("Proofs are not available when finding unsatisfiable cores", nil)
+		}
+		if len(satfile.Cardinalities) > 0 {
+			return solver, handler.Throw("Unsatisfiable cores are not available with cardinality constraints", nil)
+		}
+		if len(satfile.XORs) > 0 {
+			return solver, handler.Throw("Unsatisfiable cores are not available with XOR constraints", nil)
+		}
+		solver.selectorBase = satfile.AtomCount
+		solver.selectorCount = uint(len(satfile.Clauses))
+		satfile = WithSelectors(satfile)
+	}
+
+	if options.Experimental {
 		solver.Construct = ConstructMapClause
 	} else {
 		solver.Construct = ConstructBaseClause
@@ -54,16 +145,153 @@ func InitializeBaseSolver(satfile types.SATFile, experimental bool) (solver Base
 	// 	solver.F = BaseFormula{Clauses: clauses}
 	// }
 
-	solver.DecisionCount = 0
+	switch options.Formula {
+	case WATCHED_FORMULA:
+		f := ConstructWatchedFormula(clauses, satfile.AtomCount)
+		solver.F = f
+		solver.Construct = f.Construct
+	case BASE_FORMULA:
+		solver.F = BaseFormula{Clauses: clauses}
+	default:
+		return solver, handler.Throw(fmt.Sprintf("Unknown formula type %v", options.Formula), nil)
+	}
+
+	if _, ok := solver.F.(Forgetter); ok && options.Reduce > 0 {
+		solver.DB = ConstructClauseDB(options.Reduce)
+	}
+
+	if options.Decay == 0 {
+		options.Decay = 0.95
+	}
+
+	switch options.Brancher {
+	case VSIDS_BRANCHER:
+		solver.Brancher = ConstructVSIDS(satfile.AtomCount, options.Decay)
+	case VMTF_BRANCHER:
+		solver.Brancher = ConstructVMTF(satfile.AtomCount)
+	case RANDOM_BRANCHER:
+		solver.Brancher = ConstructRandomBrancher(satfile.AtomCount, options.Seed)
+	default:
+		return solver, handler.Throw(fmt.Sprintf("Unknown brancher type %v", options.Brancher), nil)
+	}
+
+	if options.Polarity > JEROSLOW_WANG_POLARITY {
+		return solver, handler.Throw(fmt.Sprintf("Unknown polarity type %v", options.Polarity), nil)
+	}
+	solver.Phase = ConstructPhase(satfile, options)
+
+	if options.RestartBase == 0 {
+		options.RestartBase = 100
+	}
+	if options.RestartGrow == 0 {
+		options.RestartGrow = 1.5
+	}
+
+	switch options.Restart {
+	case NO_RESTART:
+		solver.Restarter = NoRestart{}
+	case LUBY_RESTART:
+		solver.Restarter = ConstructLubyRestart(options.RestartBase)
+	case GEOMETRIC_RESTART:
+		solver.Restarter = ConstructGeometricRestart(options.RestartBase, options.RestartGrow)
+	case GLUCOSE_RESTART:
+		solver.Restarter = ConstructGlucoseRestart()
+	default:
+		return solver, handler.Throw(fmt.Sprintf("Unknown restart type %v", options.Restart), nil)
+	}
+
+	if options.Proof != nil {
+		solver.Proof = proof.ConstructDRATWriter(options.Proof, options.BinaryProof)
+	}
+
+	solver.ConflictLimit = options.Conflicts
+	solver.DecisionLimit = options.Decisions
+
+	if options.Interval == 0 {
+		options.Interval = time.Second
+	}
+	solver.Progress = options.Progress
+	solver.ProgressInterval = options.Interval
+
+	solver.MinimizeRecursive = options.Minimize
+	solver.MinimizeBinary = options.Binary
+	solver.marks = make([]uint8, satfile.AtomCount+1)
+
 	solver.AtomCount = satfile.AtomCount
-	solver.Check = make([]*ModelElement, satfile.AtomCount+1)
+	solver.Trail = ConstructTrail(satfile.AtomCount)
+
+	for _, c := range satfile.Cardinalities {
+		if err := solver.AddCardinality(c); err != nil {
+			return solver, err
+		}
+	}
+	if len(satfile.XORs) > 0 {
+		if err := solver.AddXOR(satfile.XORs...); err != nil {
+			return solver, err
+		}
+	}
 
 	return solver, nil
 }
 
-func (solver BaseCDCLSolver) Solve() (types.Solution, error) {
+/*
+Solve searches for a model of the Formula in which all the assumptions hold, refer SolveContext.
+*/
+func (solver *BaseCDCLSolver) Solve(assumptions ...types.Literal) (types.Solution, error) {
+	return solver.SolveContext(context.Background(), assumptions...)
+}
+
+/*
+SolveContext searches for a model of the Formula in which all the assumptions hold.
+
+The Trail is backjumped to level 0 first, so the literals assigned at level 0 and the learnt clauses
+of earlier calls are kept. If the result is UNSATISFIABLE because of the assumptions, Failed returns
+the assumptions responsible for it.
+
+The context and the limits of the solver are checked at every conflict and decision, the result is
+UNKNOWN without an error once one of them stops the search, and Stopped returns the reason.
+*/
+func (solver *BaseCDCLSolver) SolveContext(ctx context.Context, assumptions ...types.Literal) (types.Solution, error) {
+	for _, l := range assumptions {
+		if l == 0 {
+			return types.UNKNOWN, handler.Throw("Assumption is not a literal", nil)
+		}
+	}
+
+	solver.startClock()
+	defer solver.stopClock()
+
+	return solver.solveWith(ctx, solver.Selected(nil), assumptions)
+}
+
+/*
+Searches for a model with the clauses of the given selectors enabled. The selector assumptions are
+decided before the assumptions, and are split from the failed assumptions into the core.
+*/
+func (solver *BaseCDCLSolver) solveWith(ctx context.Context, selectors []types.Literal, assumptions []types.Literal) (types.Solution, error) {
+	solution, err := solver.search(ctx, append(selectors, assumptions...))
+	solver.failed, solver.core = solver.SplitFailed(solver.failed)
+	return solution, err
+}
+
+// The CDCL loop, which decides the assumptions before any other atom
+func (solver *BaseCDCLSolver) search(ctx context.Context, assumptions []types.Literal) (types.Solution, error) {
 	var err error
 
+	solver.failed = nil
+	solver.model = nil
+	solver.stopped = NOT_STOPPED
+	if solver.inconsistent {
+		return types.UNSATISFIABLE, nil
+	}
+
+	for _, l := range assumptions {
+		solver.Extend(uint(l.Atom()))
+	}
+	solver.Assumptions = assumptions
+	solver.Backjump(0)
+
+	conflicts, decisions := solver.Stats.Conflicts, solver.Stats.Decisions
 	currentState := types.PROGRESS
 
 	for currentState == types.PROGRESS {
@@ -77,12 +305,19 @@ func (solver BaseCDCLSolver) Solve() (types.Solution, error) {
 			else the problem is unsatisfiable
 		*/
 		case types.EMPTY_CLAUSE:
-			if solver.DecisionCount == 0 {
+			if solver.Trail.DecisionLevel() == 0 {
+				solver.inconsistent = true
+				if solver.Proof != nil {
+					solver.Proof.Add(types.Disjunction{})
+				}
 				return types.UNSATISFIABLE, nil
+			} else if solver.Stop(ctx, conflicts, decisions) {
+				return types.UNKNOWN, nil
 			} else {
 				if err = solver.ResolveConflict(currClause); err != nil {
 					return types.UNKNOWN, err
 				}
+				solver.Report()
 			}
 		/*
 			If clause is a unit clause, we perform unit propagtion
@@ -92,77 +327,135 @@ func (solver BaseCDCLSolver) Solve() (types.Solution, error) {
 				return types.UNKNOWN, handler.Throw("Unit Propagation Failed", err)
 			}
 		/*
-			We dont have any unit clauses or empty clauses, hence we decide on a literal
+			We dont have any unit clauses or empty clauses, hence we restart if the
+			restart policy asks us to, otherwise we decide on the next assumption or literal
 		*/
 		case types.DECISION_CLAUSE:
-			if err = solver.Decide(currClause); err != nil {
+			if solver.Stop(ctx, conflicts, decisions) {
+				return types.UNKNOWN, nil
+			}
+			if solver.DB != nil && solver.DB.Due() {
+				solver.ReduceDB()
+			}
+			if solver.Restarter.Due() {
+				solver.Restart()
+			} else if solver.Trail.DecisionLevel() < uint(len(solver.Assumptions)) {
+				if !solver.Assume() {
+					return types.UNSATISFIABLE, nil
+				}
+			} else if err = solver.Decide(); err != nil {
 				return types.UNKNOWN, handler.Throw("Decide Failed", err)
 			}
 		/*
 			If we get a solved clause as our next clause, this means all clauses
 			in formula are true in the given model, hence the solution is satisfiable
+			once every assumption holds
 		*/
 		case types.SOLVED_CLAUSE:
-			return types.SATISFIABLE, nil
+			if solver.Trail.DecisionLevel() >= uint(len(solver.Assumptions)) {
+				solver.SaveModel()
+				return types.SATISFIABLE, nil
+			}
+			if !solver.Assume() {
+				return types.UNSATISFIABLE, nil
+			}
 		}
 	}
 	return currentState, err
 }
 
+/*
+SaveModel copies the Trail into the Model, as the Trail does not survive the next call of Solve or AddClause.
+Atoms left unassigned do not occur in any unsolved clause, they are set to false.
+*/
+func (solver *BaseCDCLSolver) SaveModel() {
+	solver.model = make([]types.Literal, solver.AtomCount)
+	for i := range solver.model {
+		atom := types.Atom(i + 1)
+		if solver.Trail.Assigned(atom) {
+			solver.model[i] = solver.Trail.Value(atom)
+		} else {
+			solver.model[i] = types.Literal(atom).Negate()
+		}
+	}
+}
+
+/*
+Model returns the assignment found by the last call of Solve if it returned SATISFIABLE, nil otherwise.
+Model()[i] is the literal of atom i+1.
+*/
+func (solver *BaseCDCLSolver) Model() []types.Literal {
+	return solver.model
+}
+
+// Returns the literal of the atom in the Model, 0 if there is no Model or the atom is not part of it
+func (solver *BaseCDCLSolver) Value(a types.Atom) types.Literal {
+	if a == 0 || int(a) > len(solver.model) {
+		return 0
+	}
+	return solver.model[a-1]
+}
+
+/*
+Propagate asserts the literals of the Trail which have not been assigned in the Formula yet
+*/
+func (solver *BaseCDCLSolver) Propagate() {
+	for ; solver.Trail.Head < len(solver.Trail.Literals); solver.Trail.Head++ {
+		solver.F = solver.F.Assign(solver.Trail.Literals[solver.Trail.Head])
+	}
+}
+
 /*
 UnitPropgate takes the only literal and appends that to our model
 */
 func (solver *BaseCDCLSolver) UnitPropagate(clause types.Clause) error {
-	if solver.Model.Size >= solver.AtomCount {
+	if solver.Trail.Size() >= solver.AtomCount {
 		return handler.Throw("Model is larger than no. of atoms", nil)
 	}
 
 	// Ideally the literal in our unit clause should not be present in the Model
 	lit := clause.Disjunction()[0]
-	if solver.Check[lit.Atom()] != nil {
+	if solver.Trail.Assigned(lit.Atom()) {
 		return handler.Throw("Atom Repeated: "+fmt.Sprint(lit), nil)
 	}
 
 	logger.Info(fmt.Sprintf("Unit propgating %v", lit))
+	solver.Stats.Propagations++
 
-	modelElem := &ModelElement{
+	solver.Trail.Pushback(ModelElement{
 		Reason:   clause,
 		Literal:  lit,
 		Decision: false,
-	}
-
-	solver.Model.Pushback(modelElem)
-	solver.Check[lit.Atom()] = modelElem
-	solver.F = solver.F.Assign(lit)
+	})
+	solver.Propagate()
 
 	return nil
 }
 
 /*
-Decide selects the first unassigned literal from the selected clause
-
-	[TODO] Random Selection of Decide variable
+Decide asks the Brancher for the next unassigned atom and the Phase for its polarity
 */
-func (solver *BaseCDCLSolver) Decide(clause types.Clause) error {
-	if solver.Model.Size >= solver.AtomCount {
+func (solver *BaseCDCLSolver) Decide() error {
+	if solver.Trail.Size() >= solver.AtomCount {
 		return handler.Throw("Model is larger than no. of atoms", nil)
 	}
-	lit := clause.Disjunction()[0]
-	if solver.Check[lit.Atom()] != nil {
-		return handler.Throw("Atom Repeated: "+fmt.Sprint(lit), nil)
+	atom := solver.Brancher.Select(&solver.Trail)
+	if atom == 0 {
+		return handler.Throw("No unassigned atom left to decide", nil)
 	}
-
-	modelElem := &ModelElement{
-		Literal:  lit,
-		Decision: true,
+	lit := solver.Phase.Select(atom)
+	if solver.Trail.Assigned(lit.Atom()) {
+		return handler.Throw("Atom Repeated: "+fmt.Sprint(lit), nil)
 	}
 
 	logger.Info(fmt.Sprintf("Deciding %v", lit))
+	solver.Stats.Decisions++
 
-	solver.DecisionCount += 1
-	solver.Model.Pushback(modelElem)
-	solver.Check[lit.Atom()] = modelElem
-	solver.F = solver.F.Assign(lit)
+	solver.Trail.Pushback(ModelElement{
+		Literal:  lit,
+		Decision: true,
+	})
+	solver.Propagate()
 
 	return nil
 }
@@ -170,83 +463,168 @@ func (solver *BaseCDCLSolver) Decide(clause types.Clause) error {
 /*
 ResolveConflict works in 3 steps
 
- 1. Calls AnalyseConflict to learn new clause
+ 1. Calls AnalyseConflict to learn new clause and minimizes it
  2. Find the last literal in Model that makes new clause true when we negate it
  3. Find decision level to which we want to BackJump
 */
 func (solver *BaseCDCLSolver) ResolveConflict(clause types.Clause) (err error) {
 
 	logger.Info(fmt.Sprintf("Conflict Detected %v", clause.Original()))
+	solver.Stats.Conflicts++
 
-	var resolved types.Clause = solver.Construct(clause.Original(), false)
+	var resolved types.Clause
 
-	if resolved, err = solver.AnalyseConflict(resolved); err != nil {
+	if resolved, err = solver.AnalyseConflict(clause); err != nil {
 		return err
 	}
+	resolved = solver.Construct(solver.Minimize(resolved.Original()), true)
+	lbd := solver.LBD(resolved.Original())
+
+	solver.Brancher.Decay()
+	solver.Phase.Conflict(&solver.Trail)
+	solver.Restarter.Conflict(lbd)
+	solver.countLearnt(lbd)
+	if solver.DB != nil {
+		solver.DB.Learn(resolved, lbd)
+		solver.DB.Conflict()
+	}
 
+	if solver.Proof != nil {
+		solver.Proof.Add(resolved.Original())
+	}
 	solver.F.Learn(resolved)
 
-	if modelElement, err := solver.Model.SearchLastLiteral(resolved); err != nil {
+	if modelElement, err := solver.Trail.SearchLastLiteral(resolved); err != nil {
 		return err
 	} else {
 		lastLit := modelElement.Literal // UIP
 		backJumpLevel := uint(0)
 
 		// Searching for Backjump level
-		for _, lit := range resolved.Disjunction() {
+		for _, lit := range resolved.Original() {
 			lit = lit.Negate()
 			if lit != lastLit {
-				decisionLvl := solver.Check[lit.Atom()].DecisionLevel
+				decisionLvl := solver.Trail.Levels[lit.Atom()]
 				if backJumpLevel < decisionLvl {
 					backJumpLevel = decisionLvl
 				}
 			}
 		}
 
-		// Backjumping to Backjump level
-		for m, er := solver.Model.PopTillLevel(backJumpLevel); er == nil; {
-
-			bLit := m.Literal
-			logger.Info(fmt.Sprintf("Popping %v", bLit))
-
-			if m.Decision {
-				solver.DecisionCount -= 1
-			}
-
-			solver.Check[bLit.Atom()] = nil
-			solver.F = solver.F.Unassign(bLit)
-
-			m, er = solver.Model.PopTillLevel(backJumpLevel)
-		}
+		solver.Backjump(backJumpLevel)
 
 		lastLit = lastLit.Negate()
 
-		modelElem := &ModelElement{
+		logger.Info(fmt.Sprintf("Appending after conflict resolve %v", lastLit))
+		solver.Stats.Propagations++
+
+		solver.Trail.Pushback(ModelElement{
 			Literal:  lastLit,
 			Decision: false,
 			Reason:   resolved,
+		})
+		solver.Propagate()
+
+		return nil
+	}
+}
+
+/*
+Backjump removes every literal above the given decision level from the Trail and the Formula
+*/
+func (solver *BaseCDCLSolver) Backjump(level uint) {
+	for _, bLit := range solver.Trail.Backjump(level) {
+		logger.Info(fmt.Sprintf("Popping %v", bLit))
+		solver.F = solver.F.Unassign(bLit)
+		solver.Brancher.Unassign(bLit.Atom())
+		solver.Phase.Save(bLit)
+	}
+}
+
+/*
+Restart backjumps to level 0 and resets the Formula, keeping the learnt clauses and the activity of atoms.
+The literals assigned at level 0 are asserted to the Formula again.
+*/
+func (solver *BaseCDCLSolver) Restart() {
+	logger.Info("Restarting")
+	solver.Stats.Restarts++
+
+	for _, l := range solver.Trail.Backjump(0) {
+		solver.Brancher.Unassign(l.Atom())
+		solver.Phase.Save(l)
+	}
+
+	solver.F = solver.F.Restart()
+	solver.Trail.Head = 0
+	solver.Propagate()
+
+	solver.Restarter.Restart()
+}
+
+/*
+ReduceDB deletes the learnt clauses selected by the ClauseDB from the Formula
+*/
+func (solver *BaseCDCLSolver) ReduceDB() {
+	deleted := solver.DB.Reduce(solver.Locked)
+	solver.F.(Forgetter).Forget(deleted)
+	solver.Stats.Deleted += uint(len(deleted))
+	if solver.Proof != nil {
+		for _, c := range deleted {
+			solver.Proof.Delete(c.Original())
 		}
+	}
 
-		logger.Info(fmt.Sprintf("Appending after conflict resolve %v", lastLit))
+	logger.Info(fmt.Sprintf("Deleted %v learnt clauses, %v deleted in total", len(deleted), solver.DB.Deleted))
+}
 
-		solver.Model.Pushback(modelElem)
-		solver.Check[lastLit.Atom()] = modelElem
-		solver.F = solver.F.Assign(lastLit)
+/*
+Locked returns true if the clause is the reason of a literal on the Trail
+*/
+func (solver *BaseCDCLSolver) Locked(c types.Clause) bool {
+	for _, l := range c.Original() {
+		if solver.Trail.Value(l.Atom()) == l && solver.Trail.Reasons[l.Atom()] == c {
+			return true
+		}
+	}
+	return false
+}
 
-		return nil
+/*
+LBD returns the literal block distance of a clause, i.e. the no of distinct decision levels among its literals
+*/
+func (solver *BaseCDCLSolver) LBD(d types.Disjunction) uint {
+	levels := make(map[uint]bool)
+	for _, l := range d {
+		if solver.Trail.Assigned(l.Atom()) {
+			levels[solver.Trail.Levels[l.Atom()]] = true
+		}
 	}
+	return uint(len(levels))
 }
 
 /*
 AnalyseConflict finds a resolvent clause by continously resolving the conflict clause with
 the reason of last literal of conflict clause to get a new conflict clause till we reach
 unique implication point
+
+Every atom seen in the conflict clause and the reasons it is resolved with is bumped in the Brancher
 */
 func (solver *BaseCDCLSolver) AnalyseConflict(clause types.Clause) (types.Clause, error) {
-	if modelElement, err := solver.Model.SearchLastLiteral(clause); err != nil {
+	seen := make(map[types.Atom]bool)
+	bump := func(d types.Disjunction) {
+		for _, l := range d {
+			if !seen[l.Atom()] {
+				seen[l.Atom()] = true
+				solver.Brancher.Bump(l.Atom())
+			}
+		}
+	}
+
+	if modelElement, err := solver.Trail.SearchLastLiteral(clause); err != nil {
 		return clause, err
 	} else {
 		lit := modelElement.Literal
+		bump(clause.Original())
 
 		for !solver.UIP(lit, clause) {
 			reason := modelElement.Reason
@@ -254,9 +632,13 @@ func (solver *BaseCDCLSolver) AnalyseConflict(clause types.Clause) (types.Clause
 				return clause, handler.Throw("null", nil)
 			}
 			logger.Info(fmt.Sprintf("Resolving with %v", reason.Original()))
+			bump(reason.Original())
+			if solver.DB != nil && reason.IsLearnt() {
+				solver.DB.Bump(reason, solver.LBD(reason.Original()))
+			}
 			clause = ResolveBaseClause(reason.Original(), clause.Original(), lit, solver.AtomCount)
 			logger.Info(fmt.Sprintf("Resolved %v", clause.Disjunction()))
-			modelElement, err = solver.Model.SearchLastLiteral(clause)
+			modelElement, err = solver.Trail.SearchLastLiteral(clause)
 			if err != nil {
 				return clause, err
 			}
@@ -273,8 +655,8 @@ UIP checks if given literal is the UIP of given clause
 func (solver *BaseCDCLSolver) UIP(lit types.Literal, clause types.Clause) bool {
 	for _, l2 := range clause.Original() {
 		l2 = l2.Negate()
-		litDL := solver.Check[lit.Atom()].DecisionLevel
-		l2DL := solver.Check[l2.Atom()].DecisionLevel
+		litDL := solver.Trail.Levels[lit.Atom()]
+		l2DL := solver.Trail.Levels[l2.Atom()]
 		if lit != l2 && l2DL == litDL {
 			return false
 		}
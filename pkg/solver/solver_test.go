@@ -2,6 +2,8 @@
 package solver_test
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	//handler "github.com/alanpjohn/go-cdcl/pkg/error"
@@ -10,24 +12,21 @@ import (
 )
 
 func Assign(s *solver.BaseCDCLSolver, lit types.Literal, decide bool, clause types.Clause) {
-	modelElem := &solver.ModelElement{
+	modelElem := solver.ModelElement{
 		Literal:  lit,
 		Decision: decide,
 	}
 
-	if decide {
-		s.DecisionCount++
-	} else {
+	if !decide {
 		modelElem.Reason = clause
 	}
 
-	s.Model.Pushback(modelElem)
-	s.Check[lit.Atom()] = modelElem
-	s.F = s.F.Assign(lit)
+	s.Trail.Pushback(modelElem)
+	s.Propagate()
 }
 
-func getSampleSolver() solver.BaseCDCLSolver {
-	var s solver.BaseCDCLSolver
+func getSampleSolver(t *testing.T) solver.BaseCDCLSolver {
+	t.Helper()
 
 	var disjunctions []types.Disjunction = []types.Disjunction{
 		{-1, 2},
@@ -40,24 +39,22 @@ func getSampleSolver() solver.BaseCDCLSolver {
 		{1, 7},
 	}
 
-	var clauses []types.Clause
-	for _, d := range disjunctions {
-		clauses = append(clauses, solver.ConstructBaseClause(d, false))
+	s, err := solver.InitializeBaseSolver(types.SATFile{
+		AtomCount:   7,
+		ClauseCount: uint(len(disjunctions)),
+		Clauses:     disjunctions,
+	}, solver.Options{Formula: solver.BASE_FORMULA})
+	if err != nil {
+		t.Fatal(err)
 	}
 
-	s.F = solver.BaseFormula{Clauses: clauses}
-
-	s.AtomCount = 7
-	s.DecisionCount = 0
-	s.Check = make([]*solver.ModelElement, 8)
-
 	return s
 }
 
 func TestBackJump1(t *testing.T) {
 
 	t.Log("Initializing Sample Solver")
-	s := getSampleSolver()
+	s := getSampleSolver(t)
 
 	// [6d 1 2 7d 3d 4 5] is the model we apply
 	Assign(&s, 6, true, solver.ConstructBaseClause(types.Disjunction{}, false))
@@ -106,7 +103,7 @@ func TestBackJump1(t *testing.T) {
 		t.Error(err)
 	}
 
-	if s.Model.Tail.Literal != types.Literal(-3) {
+	if s.Trail.ModelList().Tail.Literal != types.Literal(-3) {
 		t.Error("Wrong conflict resolution")
 	}
 
@@ -121,7 +118,7 @@ func TestBackJump1(t *testing.T) {
 func TestBackJump2(t *testing.T) {
 
 	t.Log("Initializing Sample Solver")
-	s := getSampleSolver()
+	s := getSampleSolver(t)
 
 	learnt := solver.ConstructBaseClause(types.Disjunction{-1, -2, -3}, true)
 
@@ -178,7 +175,36 @@ func TestBackJump2(t *testing.T) {
 		t.Error(err)
 	}
 
-	if s.Model.Tail.Literal != types.Literal(-6) {
+	if s.Trail.ModelList().Tail.Literal != types.Literal(-6) {
 		t.Error("Wrong conflict resolution")
 	}
 }
+
+func TestProof(t *testing.T) {
+	var out bytes.Buffer
+	s, err := solver.InitializeBaseSolver(types.SATFile{
+		AtomCount: 2,
+		Clauses: []types.Disjunction{
+			{1, 2},
+			{1, -2},
+			{-1, 2},
+			{-1, -2},
+		},
+	}, solver.Options{Proof: &out})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if solution, _ := s.Solve(); solution != types.UNSATISFIABLE {
+		t.Fatalf("Expected UNSATISFIABLE, found %v", solution)
+	}
+	if err = s.Proof.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The learnt unit is followed by the empty clause
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) != 3 || len(strings.Fields(lines[0])) != 2 || lines[1] != "0" {
+		t.Errorf("Unexpected proof %q", out.String())
+	}
+}
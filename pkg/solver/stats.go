@@ -0,0 +1,75 @@
+package solver
+
+/*
+The stats file keeps the statistics of the search and reports the progress of long searches as `c` lines.
+*/
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+// Header of the progress lines, printed before the first of them
+const PROGRESS_HEADER = "c progress     time  conflicts  decisions propagations restarts   learnt  deleted    lbd   memory\n"
+
+/*
+Statistics returns the statistics of the solver with the time spent in Solve and the memory allocated so far
+*/
+func (solver *BaseCDCLSolver) Statistics() types.Stats {
+	stats := solver.Stats
+
+	elapsed := solver.elapsed
+	if !solver.started.IsZero() {
+		elapsed += time.Since(solver.started)
+	}
+	stats.Time = elapsed.Seconds()
+
+	var memory runtime.MemStats
+	runtime.ReadMemStats(&memory)
+	stats.Memory = memory.HeapAlloc
+
+	return stats
+}
+
+// Starts the clock of a call of Solve
+func (solver *BaseCDCLSolver) startClock() {
+	solver.started = time.Now()
+	if solver.reported.IsZero() {
+		solver.reported = solver.started
+	}
+}
+
+// Stops the clock of a call of Solve, adding its time to the statistics
+func (solver *BaseCDCLSolver) stopClock() {
+	solver.elapsed += time.Since(solver.started)
+	solver.started = time.Time{}
+}
+
+// Updates the statistics with a clause learnt from a conflict
+func (solver *BaseCDCLSolver) countLearnt(lbd uint) {
+	solver.Stats.Learnt++
+	solver.Stats.AverageLBD += (float64(lbd) - solver.Stats.AverageLBD) / float64(solver.Stats.Learnt)
+}
+
+/*
+Report writes a progress line if the progress interval passed since the last one. Write errors are
+ignored, as they must not stop the search.
+*/
+func (solver *BaseCDCLSolver) Report() {
+	if solver.Progress == nil || time.Since(solver.reported) < solver.ProgressInterval {
+		return
+	}
+
+	if solver.reports == 0 {
+		fmt.Fprint(solver.Progress, PROGRESS_HEADER)
+	}
+	s := solver.Statistics()
+	fmt.Fprintf(solver.Progress, "c progress %7.1fs %10v %10v %12v %8v %8v %8v %6.2f %6.1fMB\n",
+		s.Time, s.Conflicts, s.Decisions, s.Propagations, s.Restarts, s.Learnt, s.Deleted, s.AverageLBD, float64(s.Memory)/(1<<20))
+
+	solver.reports++
+	solver.reported = time.Now()
+}
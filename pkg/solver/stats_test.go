@@ -0,0 +1,40 @@
+package solver_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestStatistics(t *testing.T) {
+	var progress strings.Builder
+	s, err := solver.InitializeBaseSolver(pigeonhole(4), solver.Options{Progress: &progress, Interval: time.Nanosecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if solution, _ := s.Solve(); solution != types.UNSATISFIABLE {
+		t.Fatalf("Expected UNSATISFIABLE, found %v", solution)
+	}
+
+	stats := s.Statistics()
+	if stats.Conflicts == 0 || stats.Learnt != stats.Conflicts || stats.Decisions == 0 || stats.Propagations == 0 {
+		t.Errorf("Expected conflicts, decisions and propagations to be counted, found %+v", stats)
+	}
+	if stats.AverageLBD <= 0 || stats.Time <= 0 || stats.Memory == 0 {
+		t.Errorf("Expected average lbd, time and memory to be measured, found %+v", stats)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(progress.String(), "\n"), "\n")
+	if lines[0] != strings.TrimSuffix(solver.PROGRESS_HEADER, "\n") || len(lines) != int(stats.Conflicts)+1 {
+		t.Errorf("Expected a progress line after every conflict, found %q", lines)
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "c progress ") {
+			t.Errorf("Progress line %q is not a comment", line)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package solver
+
+import (
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+Trail is the array based implementation of the Model.
+
+Literals are stored in the order of assignment while the decision level, reason and position
+of every atom are stored in arrays indexed by the atom. This makes looking up an atom O(1)
+and backjumping a truncate of the Literals array.
+*/
+type Trail struct {
+	Literals  []types.Literal // Literals in the order they were assigned
+	Levels    []uint          // Levels[a] is the decision level at which atom a was assigned
+	Reasons   []types.Clause  // Reasons[a] is the reason for the assignment of atom a, nil for decisions
+	Positions []int           // Positions[a] is the index of atom a in Literals, -1 if unassigned
+	Decisions []int           // Decisions[i] is the index in Literals of the decision literal of level i+1
+	Head      int             // Index in Literals of the next literal to be propagated
+}
+
+// Constructs an empty Trail for the given number of atoms
+func ConstructTrail(atomCount uint) Trail {
+	t := Trail{
+		Literals:  make([]types.Literal, 0, atomCount),
+		Levels:    make([]uint, atomCount+1),
+		Reasons:   make([]types.Clause, atomCount+1),
+		Positions: make([]int, atomCount+1),
+	}
+	for a := range t.Positions {
+		t.Positions[a] = -1
+	}
+	return t
+}
+
+// Extends the Trail to hold the given number of atoms
+func (t *Trail) Extend(atomCount uint) {
+	for a := uint(len(t.Positions)); a <= atomCount; a++ {
+		t.Levels = append(t.Levels, 0)
+		t.Reasons = append(t.Reasons, nil)
+		t.Positions = append(t.Positions, -1)
+	}
+}
+
+// No of literals in the Trail
+func (t *Trail) Size() uint {
+	return uint(len(t.Literals))
+}
+
+// No of decision literals in the Trail
+func (t *Trail) DecisionLevel() uint {
+	return uint(len(t.Decisions))
+}
+
+// Returns the literal assigned to the atom, 0 if the atom is unassigned
+func (t *Trail) Value(a types.Atom) types.Literal {
+	if t.Positions[a] < 0 {
+		return 0
+	}
+	return t.Literals[t.Positions[a]]
+}
+
+// Returns true if the atom has been assigned
+func (t *Trail) Assigned(a types.Atom) bool {
+	return t.Positions[a] >= 0
+}
+
+// Returns the ModelElement of an assigned atom
+func (t *Trail) Element(a types.Atom) ModelElement {
+	pos := t.Positions[a]
+	return ModelElement{
+		Literal:       t.Literals[pos],
+		Reason:        t.Reasons[a],
+		Decision:      t.Levels[a] > 0 && t.Decisions[t.Levels[a]-1] == pos,
+		DecisionLevel: t.Levels[a],
+	}
+}
+
+// Pushing Literal assignment to the Trail
+func (t *Trail) Pushback(m ModelElement) {
+	a := m.Literal.Atom()
+	if m.Decision {
+		t.Decisions = append(t.Decisions, len(t.Literals))
+		t.Reasons[a] = nil
+	} else {
+		t.Reasons[a] = m.Reason
+	}
+	t.Levels[a] = t.DecisionLevel()
+	t.Positions[a] = len(t.Literals)
+	t.Literals = append(t.Literals, m.Literal)
+}
+
+/*
+NewLevel opens a decision level without a decision literal.
+It is used for assumptions which already hold, so that every assumption keeps its own level.
+*/
+func (t *Trail) NewLevel() {
+	t.Decisions = append(t.Decisions, len(t.Literals))
+}
+
+/*
+Finding Last Literal that refutes given Clause. Used for conflict resolution
+
+Only the literals of the clause are looked at, hence the search is linear in the size of the clause.
+*/
+func (t *Trail) SearchLastLiteral(clause types.Clause) (ModelElement, error) {
+	last := -1
+	for _, l := range clause.Original() {
+		pos := t.Positions[l.Atom()]
+		if pos > last && t.Literals[pos] == l.Negate() {
+			last = pos
+		}
+	}
+
+	if last < 0 {
+		return ModelElement{}, handler.Throw("Literal Not Found", nil)
+	}
+	return t.Element(t.Literals[last].Atom()), nil
+}
+
+/*
+Backjump truncates the Trail to the given decision level and returns the removed literals.
+
+The returned slice shares memory with the Trail and is only valid till the next Pushback.
+*/
+func (t *Trail) Backjump(level uint) []types.Literal {
+	if level >= t.DecisionLevel() {
+		return nil
+	}
+
+	pos := t.Decisions[level]
+	popped := t.Literals[pos:]
+	for _, l := range popped {
+		t.Positions[l.Atom()] = -1
+		t.Reasons[l.Atom()] = nil
+	}
+
+	t.Literals = t.Literals[:pos]
+	t.Decisions = t.Decisions[:level]
+	if t.Head > pos {
+		t.Head = pos
+	}
+
+	return popped
+}
+
+// Builds a ModelList view of the Trail
+func (t *Trail) ModelList() ModelList {
+	var modelList ModelList
+	for _, l := range t.Literals {
+		m := t.Element(l.Atom())
+		modelList.Pushback(&m)
+	}
+	return modelList
+}
@@ -0,0 +1,65 @@
+package solver_test
+
+import (
+	"testing"
+
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func getSampleTrail() solver.Trail {
+	trail := solver.ConstructTrail(40)
+
+	for i := 1; i < 20; i++ {
+		trail.Pushback(solver.ModelElement{
+			Literal:  types.Literal(i),
+			Decision: i%5 == 3,
+		})
+	}
+
+	return trail
+}
+
+func TestTrailSearchLastLiteral(t *testing.T) {
+	trail := getSampleTrail()
+
+	d1 := types.Disjunction{-2, -4, 5}
+	m1, err1 := trail.SearchLastLiteral(solver.ConstructBaseClause(d1, false))
+
+	if err1 != nil {
+		t.Errorf("%v\n", err1)
+	}
+
+	if m1.Literal != types.Literal(4) || m1.DecisionLevel != 1 {
+		t.Errorf("Wrong last element %v at level %v of %v", m1.Literal, m1.DecisionLevel, d1)
+	}
+
+	d2 := types.Disjunction{1, 2, 3}
+	_, err2 := trail.SearchLastLiteral(solver.ConstructBaseClause(d2, false))
+
+	if err2 == nil {
+		t.Errorf("Should have thrown not found error")
+	}
+}
+
+func TestTrailBackjump(t *testing.T) {
+	trail := getSampleTrail()
+
+	popped := trail.Backjump(2)
+	if len(popped) != 7 || popped[0] != types.Literal(13) {
+		t.Errorf("Incorrect literals popped %v\n", popped)
+	}
+
+	if trail.DecisionLevel() != 2 || trail.Size() != 12 {
+		t.Errorf("Incorrect level %v and size %v after backjump\n", trail.DecisionLevel(), trail.Size())
+	}
+
+	if trail.Assigned(13) || !trail.Assigned(12) {
+		t.Errorf("Incorrect assignment after backjump\n")
+	}
+
+	view := trail.ModelList()
+	if view.Tail.Literal != types.Literal(12) || view.DecisionLevel != 2 || !view.Tail.Prev.Prev.Prev.Prev.Decision {
+		t.Errorf("Incorrect ModelList view %v\n", view.Tail.Literal)
+	}
+}
@@ -0,0 +1,95 @@
+package solver
+
+/*
+The verify file checks models found by the solver, or any other solver, against the input formula.
+*/
+
+import (
+	"fmt"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+VerifyModel returns the indices of the clauses of the SATFile which are not satisfied by the model.
+Cardinality constraints are numbered after the clauses, and XOR constraints after them.
+
+A clause is satisfied if one of its literals is in the model, atoms missing from the model satisfy
+none of their literals. A cardinality constraint is satisfied if at least Bound of its distinct
+literals are in the model. An XOR constraint is satisfied if an odd no of its literals are in the
+model, with a literal occurring twice counted twice. An error is returned if the model assigns an
+atom both ways.
+*/
+func VerifyModel(sat types.SATFile, model []types.Literal) ([]int, error) {
+	values := make(map[types.Atom]types.Literal, len(model))
+	for _, l := range model {
+		if l == 0 {
+			continue
+		}
+		if v, ok := values[l.Atom()]; ok && v != l {
+			return nil, handler.Throw(fmt.Sprintf("Model assigns atom %v both ways", l.Atom()), nil)
+		}
+		values[l.Atom()] = l
+	}
+
+	var violated []int
+	for i, d := range sat.Clauses {
+		satisfied := false
+		for _, l := range d {
+			if values[l.Atom()] == l {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			violated = append(violated, i)
+		}
+	}
+
+	for i, c := range sat.Cardinalities {
+		var count uint
+		for _, l := range normalize(c.Literals) {
+			if values[l.Atom()] == l {
+				count++
+			}
+		}
+		if count < c.Bound {
+			violated = append(violated, len(sat.Clauses)+i)
+		}
+	}
+
+	for i, x := range sat.XORs {
+		odd := false
+		for _, l := range x.Literals {
+			if values[l.Atom()] == l {
+				odd = !odd
+			}
+		}
+		if !odd {
+			violated = append(violated, len(sat.Clauses)+len(sat.Cardinalities)+i)
+		}
+	}
+
+	return violated, nil
+}
+
+/*
+VerifyPB returns the indices of the pseudo-Boolean constraints which are not satisfied by the model,
+atoms missing from the model count as false.
+*/
+func VerifyPB(constraints []types.PBConstraint, model []types.Literal) []int {
+	var violated []int
+	for i, c := range constraints {
+		var sum int64
+		for _, t := range c.Terms {
+			if a := int(t.Literal.Atom()); a <= len(model) && model[a-1] == t.Literal || a > len(model) && t.Literal < 0 {
+				sum += t.Coefficient
+			}
+		}
+		if sum < c.Degree {
+			violated = append(violated, i)
+		}
+	}
+	return violated
+}
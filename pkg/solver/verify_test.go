@@ -0,0 +1,34 @@
+package solver_test
+
+import (
+	"testing"
+
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestVerifyModel(t *testing.T) {
+	sat := types.SATFile{
+		AtomCount: 3,
+		Clauses: []types.Disjunction{
+			{1, -2},
+			{2, 3},
+			{-1, -3},
+		},
+	}
+
+	violated, err := solver.VerifyModel(sat, []types.Literal{1, 2, -3})
+	if err != nil || len(violated) != 0 {
+		t.Errorf("Expected model to satisfy every clause, found %v %v", violated, err)
+	}
+
+	// Atom 3 is missing from the model, hence it satisfies neither 3 nor -3
+	violated, _ = solver.VerifyModel(sat, []types.Literal{-1, -2})
+	if len(violated) != 1 || violated[0] != 1 {
+		t.Errorf("Expected clause 1 to be violated, found %v", violated)
+	}
+
+	if _, err = solver.VerifyModel(sat, []types.Literal{1, -1}); err == nil {
+		t.Errorf("Expected an error for an atom assigned both ways")
+	}
+}
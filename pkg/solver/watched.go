@@ -0,0 +1,467 @@
+package solver
+
+/*
+The watched file contains the two watched literal implementation of the Clause and Formula interfaces.
+
+Instead of rewriting every clause on each assignment, every clause watches two of its literals.
+A clause only needs to be looked at when one of its watched literals is refuted, and undoing an
+assignment never requires touching a clause at all.
+*/
+
+import (
+	"fmt"
+
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+WatchedClause implements the Clause interface for the WatchedFormula.
+
+The first two literals of the clause are the watched literals. The clause does not store any
+state of its own, the assignment is read from the formula it belongs to.
+*/
+type WatchedClause struct {
+	literals types.Disjunction // literals[0] and literals[1] are the watched literals
+	learnt   bool
+	deleted  bool // Set when the clause is forgotten
+	formula  *WatchedFormula
+}
+
+func (c *WatchedClause) Type() types.ClauseType {
+	unassigned := 0
+	for _, l := range c.literals {
+		switch c.formula.state(l) {
+		case ASSIGNED:
+			return types.SOLVED_CLAUSE
+		case UNASSIGNED:
+			unassigned++
+		}
+	}
+
+	if unassigned == 0 {
+		return types.EMPTY_CLAUSE
+	} else if unassigned == 1 {
+		return types.UNIT_CLAUSE
+	}
+	return types.DECISION_CLAUSE
+}
+
+// Assignments are tracked by the WatchedFormula, hence Apply does not change the clause
+func (c *WatchedClause) Apply(l types.Literal) types.Clause {
+	return c
+}
+
+// Assignments are tracked by the WatchedFormula, hence Undo does not change the clause
+func (c *WatchedClause) Undo(l types.Literal) types.Clause {
+	return c
+}
+
+// Assignments are tracked by the WatchedFormula, hence Reset does not change the clause
+func (c *WatchedClause) Reset() types.Clause {
+	return c
+}
+
+func (c *WatchedClause) Contains(l types.Literal) bool {
+	for _, cl := range c.literals {
+		if cl == l {
+			return c.formula.state(l) != REFUTED
+		}
+	}
+	return false
+}
+
+func (c *WatchedClause) IsSolved() bool {
+	for _, l := range c.literals {
+		if c.formula.state(l) == ASSIGNED {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *WatchedClause) IsLearnt() bool {
+	return c.learnt
+}
+
+// Returns the literals of the clause which are not refuted in the current model
+func (c *WatchedClause) Disjunction() types.Disjunction {
+	var d types.Disjunction
+	for _, l := range c.literals {
+		if c.formula.state(l) != REFUTED {
+			d = append(d, l)
+		}
+	}
+	return d
+}
+
+func (c *WatchedClause) Original() types.Disjunction {
+	return c.literals
+}
+
+/*
+WatchedFormula implements the Formula interface using two watched literals per clause.
+
+Every literal has a watch list of the clauses watching it. On assignment only the clauses
+watching the refuted literal are visited, and clauses that become unit or empty are queued
+so that NextClause does not have to scan the formula.
+*/
+type WatchedFormula struct {
+	Clauses   []*WatchedClause   // All clauses in the formula including learnt clauses
+	AtomCount uint               // No of atoms
+	watches   [][]*WatchedClause // watches[index(l)] holds the clauses watching literal l
+	values    []types.Literal    // values[a] is the literal of atom a in the model, 0 if unassigned
+	stamps    []uint             // stamps[a] is the order in which atom a was assigned
+	clock     uint               // Incremented on every assignment to stamp atoms
+	short     []*WatchedClause   // Empty and unit clauses which cannot be watched
+	conflicts []types.Clause     // Clauses found to be empty during propagation, including explanations of Constraints
+	units     []types.Clause     // Clauses found to be unit during propagation, including explanations of Constraints
+	head      int                // Position of the next unit clause to be considered
+	cursor    types.Atom         // Every atom below cursor is assigned
+
+	constraints []Constraint   // Constraints which are not disjunctions. Refer `constraint.go`
+	triggers    [][]Constraint // triggers[index(l)] holds the constraints told when l is assigned
+}
+
+// Constructs an instance of WatchedFormula from Clauses
+func ConstructWatchedFormula(clauses []types.Clause, atomCount uint) *WatchedFormula {
+	f := &WatchedFormula{
+		AtomCount: atomCount,
+		watches:   make([][]*WatchedClause, 2*(atomCount+1)),
+		triggers:  make([][]Constraint, 2*(atomCount+1)),
+		values:    make([]types.Literal, atomCount+1),
+		stamps:    make([]uint, atomCount+1),
+		cursor:    1,
+	}
+
+	for _, c := range clauses {
+		f.add(f.Construct(c.Original(), c.IsLearnt()).(*WatchedClause))
+	}
+
+	return f
+}
+
+// Extends the formula to hold the given number of atoms
+func (f *WatchedFormula) Extend(atomCount uint) {
+	for a := f.AtomCount + 1; a <= atomCount; a++ {
+		f.watches = append(f.watches, nil, nil)
+		f.triggers = append(f.triggers, nil, nil)
+		f.values = append(f.values, 0)
+		f.stamps = append(f.stamps, 0)
+	}
+	if atomCount > f.AtomCount {
+		f.AtomCount = atomCount
+	}
+}
+
+/*
+Construct wraps a Disjunction into a WatchedClause of the formula.
+The clause is not part of the formula till it is learnt.
+*/
+func (f *WatchedFormula) Construct(d types.Disjunction, learnt bool) types.Clause {
+	return &WatchedClause{
+		literals: normalize(d),
+		learnt:   learnt,
+		formula:  f,
+	}
+}
+
+// Index of the watch list of a literal
+func index(l types.Literal) int {
+	if l < 0 {
+		return 2*int(l.Atom()) + 1
+	}
+	return 2 * int(l.Atom())
+}
+
+// State of a literal in the current model
+func (f *WatchedFormula) state(l types.Literal) LiteralState {
+	switch f.values[l.Atom()] {
+	case 0:
+		return UNASSIGNED
+	case l:
+		return ASSIGNED
+	}
+	return REFUTED
+}
+
+// Removes repeated literals so that a clause never watches the same literal twice
+func normalize(d types.Disjunction) types.Disjunction {
+	var out types.Disjunction
+	for _, l := range d {
+		repeated := false
+		for _, o := range out {
+			if o == l {
+				repeated = true
+				break
+			}
+		}
+		if !repeated {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+/*
+Adds a clause to the formula and picks its watched literals.
+
+Literals which are not refuted are preferred as watches, followed by the refuted literals which
+were assigned last. A learnt clause is added while all of its literals are refuted, this choice
+makes sure the clause watches the UIP and the literal with the highest decision level below it.
+*/
+func (f *WatchedFormula) add(c *WatchedClause) {
+	f.Clauses = append(f.Clauses, c)
+
+	if len(c.literals) < 2 {
+		f.short = append(f.short, c)
+	} else {
+		for w := 0; w < 2; w++ {
+			best := w
+			for i := w + 1; i < len(c.literals); i++ {
+				if f.prefer(c.literals[i], c.literals[best]) {
+					best = i
+				}
+			}
+			c.literals[w], c.literals[best] = c.literals[best], c.literals[w]
+			f.watches[index(c.literals[w])] = append(f.watches[index(c.literals[w])], c)
+		}
+	}
+
+	f.enqueue(c)
+}
+
+// Returns true if l1 makes for a better watched literal than l2
+func (f *WatchedFormula) prefer(l1, l2 types.Literal) bool {
+	s1, s2 := f.state(l1), f.state(l2)
+	if s1 != REFUTED || s2 != REFUTED {
+		return s1 != REFUTED && s2 == REFUTED
+	}
+	return f.stamps[l1.Atom()] > f.stamps[l2.Atom()]
+}
+
+// Queues the clause if it is empty or unit in the current model
+func (f *WatchedFormula) enqueue(c types.Clause) {
+	switch c.Type() {
+	case types.EMPTY_CLAUSE:
+		f.conflicts = append(f.conflicts, c)
+	case types.UNIT_CLAUSE:
+		f.units = append(f.units, c)
+	}
+}
+
+/*
+NextClause returns the queued empty clauses first and then the queued unit clauses.
+Queued clauses are checked again before being returned as backjumping may have changed their type.
+
+When there are no empty or unit clauses, an unresolved clause watching the first unassigned atom
+is returned for the solver to decide on.
+*/
+func (f *WatchedFormula) NextClause() types.Clause {
+	for len(f.conflicts) > 0 {
+		c := f.conflicts[len(f.conflicts)-1]
+		f.conflicts = f.conflicts[:len(f.conflicts)-1]
+		if c.Type() == types.EMPTY_CLAUSE {
+			return c
+		}
+	}
+
+	for f.head < len(f.units) {
+		c := f.units[f.head]
+		f.head++
+		if t := c.Type(); t == types.UNIT_CLAUSE || t == types.EMPTY_CLAUSE {
+			return c
+		}
+	}
+	f.units = f.units[:0]
+	f.head = 0
+
+	for ; f.cursor <= types.Atom(f.AtomCount); f.cursor++ {
+		if f.values[f.cursor] != 0 {
+			continue
+		}
+		lit := types.Literal(f.cursor)
+		for _, l := range []types.Literal{lit, lit.Negate()} {
+			for _, c := range f.watches[index(l)] {
+				if !c.IsSolved() {
+					return c
+				}
+			}
+		}
+		// The atom does not occur in any unresolved clause, so either polarity will do
+		return &WatchedClause{literals: types.Disjunction{lit, lit.Negate()}, formula: f}
+	}
+
+	// Every atom is assigned and no conflict is pending, hence every clause is solved
+	return BaseClause{solved: 1}
+}
+
+/*
+Assign visits only the clauses watching the negation of the literal. Each of them either finds
+a new literal to watch, or is queued as a unit or empty clause.
+*/
+func (f *WatchedFormula) Assign(l types.Literal) types.Formula {
+	f.values[l.Atom()] = l
+	f.clock++
+	f.stamps[l.Atom()] = f.clock
+
+	refuted := l.Negate()
+	watchers := f.watches[index(refuted)]
+	kept := watchers[:0]
+
+	for _, c := range watchers {
+		// Keep the refuted literal at position 1
+		if c.literals[0] == refuted {
+			c.literals[0], c.literals[1] = c.literals[1], c.literals[0]
+		}
+
+		if f.state(c.literals[0]) == ASSIGNED {
+			kept = append(kept, c)
+			continue
+		}
+
+		moved := false
+		for i := 2; i < len(c.literals); i++ {
+			if f.state(c.literals[i]) != REFUTED {
+				c.literals[1], c.literals[i] = c.literals[i], c.literals[1]
+				f.watches[index(c.literals[1])] = append(f.watches[index(c.literals[1])], c)
+				moved = true
+				break
+			}
+		}
+		if moved {
+			continue
+		}
+
+		kept = append(kept, c)
+		if f.state(c.literals[0]) == UNASSIGNED {
+			f.units = append(f.units, c)
+		} else {
+			f.conflicts = append(f.conflicts, c)
+		}
+	}
+
+	// Clear the dropped tail so moved clauses are not kept alive by this watch list
+	for i := len(kept); i < len(watchers); i++ {
+		watchers[i] = nil
+	}
+	f.watches[index(refuted)] = kept
+
+	for _, c := range f.triggers[index(l)] {
+		c.Assign(l)
+	}
+
+	return f
+}
+
+// Unassign only clears the atom, the watched literals stay valid after backjumping
+func (f *WatchedFormula) Unassign(l types.Literal) types.Formula {
+	if f.values[l.Atom()] == l {
+		for _, c := range f.triggers[index(l)] {
+			c.Unassign(l)
+		}
+	}
+	f.values[l.Atom()] = 0
+	if l.Atom() < f.cursor {
+		f.cursor = l.Atom()
+	}
+	return f
+}
+
+// Learn adds clauses constructed by the formula as they are, other clauses are copied
+func (f *WatchedFormula) Learn(c types.Clause) types.Formula {
+	if wc, ok := c.(*WatchedClause); ok && wc.formula == f {
+		f.add(wc)
+	} else {
+		f.add(f.Construct(c.Original(), true).(*WatchedClause))
+	}
+	return f
+}
+
+/*
+Forget deletes the given clauses from the formula.
+The clauses are marked first so that every watch list only has to be filtered once.
+*/
+func (f *WatchedFormula) Forget(clauses []types.Clause) {
+	if len(clauses) == 0 {
+		return
+	}
+
+	for _, c := range clauses {
+		c.(*WatchedClause).deleted = true
+	}
+
+	for i, watchers := range f.watches {
+		kept := watchers[:0]
+		for _, c := range watchers {
+			if !c.deleted {
+				kept = append(kept, c)
+			}
+		}
+		for j := len(kept); j < len(watchers); j++ {
+			watchers[j] = nil
+		}
+		f.watches[i] = kept
+	}
+
+	short := f.short[:0]
+	for _, c := range f.short {
+		if !c.deleted {
+			short = append(short, c)
+		}
+	}
+	f.short = short
+
+	kept := f.Clauses[:0]
+	for _, c := range f.Clauses {
+		if !c.deleted {
+			kept = append(kept, c)
+		}
+	}
+	for j := len(kept); j < len(f.Clauses); j++ {
+		f.Clauses[j] = nil
+	}
+	f.Clauses = kept
+}
+
+// Returns the other literal of every binary clause containing l, binary clauses always watch both literals
+func (f *WatchedFormula) Binaries(l types.Literal) []types.Literal {
+	var out []types.Literal
+	for _, c := range f.watches[index(l)] {
+		if len(c.literals) == 2 {
+			if c.literals[0] == l {
+				out = append(out, c.literals[1])
+			} else {
+				out = append(out, c.literals[0])
+			}
+		}
+	}
+	return out
+}
+
+func (f *WatchedFormula) Restart() types.Formula {
+	for a := range f.values {
+		f.values[a] = 0
+	}
+	f.conflicts = f.conflicts[:0]
+	f.units = f.units[:0]
+	f.head = 0
+	f.cursor = 1
+
+	for _, c := range f.short {
+		f.enqueue(c)
+	}
+	for _, c := range f.constraints {
+		c.Reset()
+		c.Check()
+	}
+
+	return f
+}
+
+func (f *WatchedFormula) Print() string {
+	var clauses []types.Disjunction
+	for _, c := range f.Clauses {
+		clauses = append(clauses, c.literals)
+	}
+	return fmt.Sprintf("%v", clauses)
+}
@@ -0,0 +1,105 @@
+package solver_test
+
+import (
+	"testing"
+
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func getWatchedFormula(disjunctions []types.Disjunction, atomCount uint) *solver.WatchedFormula {
+	var clauses []types.Clause
+	for _, d := range disjunctions {
+		clauses = append(clauses, solver.ConstructBaseClause(d, false))
+	}
+	return solver.ConstructWatchedFormula(clauses, atomCount)
+}
+
+func TestWatchedPropagation(t *testing.T) {
+	f := getWatchedFormula([]types.Disjunction{
+		{-1, 2},
+		{-1, -2, 3},
+		{4, 5, 6},
+	}, 6)
+
+	if c := f.NextClause(); c.Type() != types.DECISION_CLAUSE {
+		t.Errorf("Expected decision clause, found %v", c.Disjunction())
+	}
+
+	f.Assign(1)
+	c := f.NextClause()
+	if c.Type() != types.UNIT_CLAUSE || c.Disjunction()[0] != 2 {
+		t.Errorf("Expected unit clause [2], found %v", c.Disjunction())
+	}
+
+	f.Assign(2)
+	c = f.NextClause()
+	if c.Type() != types.UNIT_CLAUSE || c.Disjunction()[0] != 3 {
+		t.Errorf("Expected unit clause [3], found %v", c.Disjunction())
+	}
+
+	f.Assign(-3)
+	if c = f.NextClause(); c.Type() != types.EMPTY_CLAUSE {
+		t.Errorf("Expected empty clause, found %v", c.Disjunction())
+	}
+
+	f.Unassign(-3)
+	f.Unassign(2)
+	f.Unassign(1)
+	if c = f.NextClause(); c.Type() != types.DECISION_CLAUSE {
+		t.Errorf("Expected decision clause after backjump, found %v", c.Disjunction())
+	}
+}
+
+func TestWatchedLearn(t *testing.T) {
+	f := getWatchedFormula([]types.Disjunction{
+		{1, 2, 3},
+	}, 4)
+
+	f.Assign(-1)
+	f.Assign(-2)
+	f.Assign(-4)
+	f.Learn(solver.ConstructBaseClause(types.Disjunction{1, 2, 4}, true))
+
+	c := f.NextClause()
+	if c.Type() != types.EMPTY_CLAUSE || !c.IsLearnt() {
+		t.Errorf("Learnt clause should be in conflict, found %v", c.Original())
+	}
+
+	// The learnt clause must watch the two literals assigned last
+	f.Unassign(-4)
+	f.Unassign(-2)
+	f.Assign(-4)
+	c = f.NextClause()
+	if c.Type() != types.UNIT_CLAUSE || c.Disjunction()[0] != 2 {
+		t.Errorf("Expected unit clause [2], found %v", c.Disjunction())
+	}
+}
+
+func TestWatchedSolve(t *testing.T) {
+	sat := types.SATFile{
+		AtomCount: 3,
+		Clauses: []types.Disjunction{
+			{1, 2},
+			{-1, 3},
+			{-2, 3},
+			{-3, 1},
+		},
+	}
+	s, err := solver.InitializeBaseSolver(sat, solver.Options{Formula: solver.WATCHED_FORMULA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol, err := s.Solve(); err != nil || sol != types.SATISFIABLE {
+		t.Errorf("Solution found : %v\nError: %v", sol, err)
+	}
+
+	sat.Clauses = append(sat.Clauses, types.Disjunction{-1, -3})
+	s, err = solver.InitializeBaseSolver(sat, solver.Options{Formula: solver.WATCHED_FORMULA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol, err := s.Solve(); err != nil || sol != types.UNSATISFIABLE {
+		t.Errorf("Solution found : %v\nError: %v", sol, err)
+	}
+}
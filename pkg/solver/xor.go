@@ -0,0 +1,420 @@
+package solver
+
+/*
+The xor file propagates XOR constraints by Gauss-Jordan elimination over GF(2), in the style of
+CryptoMiniSat. Parity constraints need exponentially many clauses, and unit propagation on their
+clauses misses what adding two constraints reveals.
+
+The XOR constraints form the rows of a matrix with a column per atom, each row is a bitset of its
+atoms and the parity they sum to. The matrix is kept in reduced row echelon form over the unassigned
+columns: every row has an unassigned pivot column which no other row contains, or no unassigned column
+at all. A row whose only unassigned column is its pivot implies the value of the pivot, and a row without
+unassigned columns of the wrong parity is a conflict. Every row is a sum of the original constraints,
+hence its clause under the current assignment explains the implication or conflict.
+
+When the pivot of a row is assigned, the row picks another unassigned column as pivot and eliminates it
+from the other rows. Backjumping does not undo the eliminations, the rows left without pivot get one
+again at the next assignment.
+*/
+
+import (
+	"fmt"
+	"math/bits"
+
+	handler "github.com/alanpjohn/go-cdcl/pkg/error"
+	logger "github.com/alanpjohn/go-cdcl/pkg/logger"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+/*
+XORClause implements the Constraint interface for a set of XOR constraints which share their
+Gauss-Jordan matrix. Like the WatchedClause, the assignment is read from the formula, the bitsets
+of assigned and true columns only mirror it.
+*/
+type XORClause struct {
+	atoms     []types.Atom       // atoms[c] is the atom of column c
+	columns   map[types.Atom]int // Column of every atom
+	rows      [][]uint64         // rows[r] is the bitset of the columns of row r
+	parity    []bool             // parity[r] is the sum of the columns of row r
+	pivots    []int              // pivots[r] is the pivot column of row r, -1 if it has none
+	pivotRows []int              // pivotRows[c] is the row with pivot column c, -1 if there is none
+	assigned  []uint64           // Bitset of the assigned columns
+	values    []uint64           // Bitset of the columns whose atom is true
+	dirty     []bool             // dirty[r] is set if row r changed since it was last checked
+	stale     bool               // Some rows without pivot may have unassigned columns
+	formula   *WatchedFormula
+}
+
+/*
+Constructs an XORClause from XOR constraints and eliminates them, the rows of the matrix are then
+linearly independent. The second result is false if the constraints contradict each other.
+*/
+func ConstructXORClause(xors []types.XOR) (*XORClause, bool) {
+	c := &XORClause{columns: make(map[types.Atom]int)}
+	for _, x := range xors {
+		for _, l := range x.Literals {
+			if _, ok := c.columns[l.Atom()]; !ok {
+				c.columns[l.Atom()] = len(c.atoms)
+				c.atoms = append(c.atoms, l.Atom())
+			}
+		}
+	}
+
+	words := (len(c.atoms) + 63) / 64
+	c.assigned = make([]uint64, words)
+	c.values = make([]uint64, words)
+	c.pivotRows = make([]int, len(c.atoms))
+	for i := range c.pivotRows {
+		c.pivotRows[i] = -1
+	}
+
+	for _, x := range xors {
+		row := make([]uint64, words)
+		parity := true
+		for _, l := range x.Literals {
+			col := c.columns[l.Atom()]
+			// A literal occurring twice cancels out, a negative literal flips the parity
+			row[col/64] ^= 1 << (col % 64)
+			if l < 0 {
+				parity = !parity
+			}
+		}
+		c.rows = append(c.rows, row)
+		c.parity = append(c.parity, parity)
+		c.pivots = append(c.pivots, -1)
+		c.dirty = append(c.dirty, true)
+	}
+
+	// Gauss-Jordan elimination, rows which sum to 0 are dropped
+	for r := 0; r < len(c.rows); r++ {
+		col := c.first(r, false)
+		if col < 0 {
+			if c.parity[r] {
+				return c, false
+			}
+			c.remove(r)
+			r--
+			continue
+		}
+		c.pivot(r, col)
+	}
+
+	return c, true
+}
+
+// Removes row r, which has no pivot
+func (c *XORClause) remove(r int) {
+	last := len(c.rows) - 1
+	c.rows[r], c.parity[r], c.pivots[r], c.dirty[r] = c.rows[last], c.parity[last], c.pivots[last], c.dirty[last]
+	if c.pivots[r] >= 0 {
+		c.pivotRows[c.pivots[r]] = r
+	}
+	c.rows, c.parity, c.pivots, c.dirty = c.rows[:last], c.parity[:last], c.pivots[:last], c.dirty[:last]
+}
+
+// Returns the first column of row r, only an unassigned one if asked to, and -1 if there is none
+func (c *XORClause) first(r int, unassigned bool) int {
+	for w, word := range c.rows[r] {
+		if unassigned {
+			word &^= c.assigned[w]
+		}
+		if word != 0 {
+			return 64*w + bits.TrailingZeros64(word)
+		}
+	}
+	return -1
+}
+
+func (c *XORClause) contains(r int, col int) bool {
+	return c.rows[r][col/64]&(1<<(col%64)) != 0
+}
+
+// Adds row src to row dst
+func (c *XORClause) add(dst int, src int) {
+	for w, word := range c.rows[src] {
+		c.rows[dst][w] ^= word
+	}
+	c.parity[dst] = c.parity[dst] != c.parity[src]
+	c.dirty[dst] = true
+}
+
+// Makes col the pivot of row r and eliminates it from the other rows
+func (c *XORClause) pivot(r int, col int) {
+	c.pivots[r] = col
+	c.pivotRows[col] = r
+	c.dirty[r] = true
+	for other := range c.rows {
+		if other != r && c.contains(other, col) {
+			c.add(other, r)
+		}
+	}
+}
+
+// Gives a pivot to every row without one which has an unassigned column
+func (c *XORClause) revive() {
+	if !c.stale {
+		return
+	}
+	c.stale = false
+	for r := range c.rows {
+		if c.pivots[r] < 0 {
+			if col := c.first(r, true); col >= 0 {
+				c.pivot(r, col)
+			}
+		}
+	}
+}
+
+// Returns the no of unassigned columns of row r, counting at most 2, and the sum of its assigned columns
+func (c *XORClause) counts(r int) (unassigned int, sum bool) {
+	ones := 0
+	for w, word := range c.rows[r] {
+		if unassigned < 2 {
+			unassigned += bits.OnesCount64(word &^ c.assigned[w])
+		}
+		ones += bits.OnesCount64(word & c.values[w])
+	}
+	return unassigned, ones%2 == 1
+}
+
+/*
+Returns the clause of row r under the current assignment, with the implied literal first unless it
+is 0. The clause refutes the assignment of the other columns of the row.
+*/
+func (c *XORClause) explanation(r int, implied types.Literal) types.Disjunction {
+	d := types.Disjunction{}
+	if implied != 0 {
+		d = append(d, implied)
+	}
+	for w, word := range c.rows[r] {
+		word &= c.assigned[w]
+		for word != 0 {
+			col := 64*w + bits.TrailingZeros64(word)
+			word &= word - 1
+			l := types.Literal(c.atoms[col])
+			if c.values[w]&(1<<(col%64)) != 0 {
+				l = l.Negate()
+			}
+			d = append(d, l)
+		}
+	}
+	return d
+}
+
+func (c *XORClause) Type() types.ClauseType {
+	solved := true
+	unit := false
+	for r := range c.rows {
+		unassigned, sum := c.counts(r)
+		switch {
+		case unassigned == 0 && sum != c.parity[r]:
+			return types.EMPTY_CLAUSE
+		case unassigned == 1 && c.pivots[r] >= 0:
+			unit = true
+		}
+		solved = solved && unassigned == 0
+	}
+	switch {
+	case unit:
+		return types.UNIT_CLAUSE
+	case solved:
+		return types.SOLVED_CLAUSE
+	}
+	return types.DECISION_CLAUSE
+}
+
+// Assignments are tracked by the WatchedFormula, hence Apply does not change the constraint
+func (c *XORClause) Apply(l types.Literal) types.Clause {
+	return c
+}
+
+// Assignments are tracked by the WatchedFormula, hence Undo does not change the constraint
+func (c *XORClause) Undo(l types.Literal) types.Clause {
+	return c
+}
+
+// Copies the current assignment of the formula, rows whose pivot is assigned give it up
+func (c *XORClause) Reset() types.Clause {
+	for w := range c.assigned {
+		c.assigned[w], c.values[w] = 0, 0
+	}
+	for col, a := range c.atoms {
+		if v := c.formula.Value(a); v != 0 {
+			c.assigned[col/64] |= 1 << (col % 64)
+			if v > 0 {
+				c.values[col/64] |= 1 << (col % 64)
+			}
+			if r := c.pivotRows[col]; r >= 0 {
+				c.pivots[r], c.pivotRows[col] = -1, -1
+			}
+		}
+	}
+	for r := range c.dirty {
+		c.dirty[r] = true
+	}
+	c.stale = true
+	return c
+}
+
+// An XOR constraint contains both literals of its atoms
+func (c *XORClause) Contains(l types.Literal) bool {
+	_, ok := c.columns[l.Atom()]
+	return ok && c.formula.state(l) == UNASSIGNED
+}
+
+func (c *XORClause) IsSolved() bool {
+	return c.Type() == types.SOLVED_CLAUSE
+}
+
+func (c *XORClause) IsLearnt() bool {
+	return false
+}
+
+// Returns the positive literals of the unassigned atoms of the constraints
+func (c *XORClause) Disjunction() types.Disjunction {
+	var d types.Disjunction
+	for _, a := range c.atoms {
+		if c.formula.Value(a) == 0 {
+			d = append(d, types.Literal(a))
+		}
+	}
+	return d
+}
+
+// Returns the positive literals of the atoms of the constraints
+func (c *XORClause) Original() types.Disjunction {
+	d := make(types.Disjunction, len(c.atoms))
+	for i, a := range c.atoms {
+		d[i] = types.Literal(a)
+	}
+	return d
+}
+
+// Returns the rows of the matrix as XOR constraints, they are equivalent to the constraints added
+func (c *XORClause) XORs() []types.XOR {
+	xors := make([]types.XOR, len(c.rows))
+	for r := range c.rows {
+		var d types.Disjunction
+		for col, a := range c.atoms {
+			if c.contains(r, col) {
+				d = append(d, types.Literal(a))
+			}
+		}
+		if !c.parity[r] {
+			d[0] = d[0].Negate()
+		}
+		xors[r] = types.XOR{Literals: d}
+	}
+	return xors
+}
+
+func (c *XORClause) Attach(f *WatchedFormula) {
+	c.formula = f
+}
+
+// The constraint is told of both values of its atoms
+func (c *XORClause) Triggers() []types.Literal {
+	triggers := make([]types.Literal, 0, 2*len(c.atoms))
+	for _, a := range c.atoms {
+		triggers = append(triggers, types.Literal(a), types.Literal(a).Negate())
+	}
+	return triggers
+}
+
+func (c *XORClause) Assign(l types.Literal) {
+	col := c.columns[l.Atom()]
+	c.assigned[col/64] |= 1 << (col % 64)
+	if l > 0 {
+		c.values[col/64] |= 1 << (col % 64)
+	}
+
+	for r := range c.rows {
+		if c.contains(r, col) {
+			c.dirty[r] = true
+		}
+	}
+	if r := c.pivotRows[col]; r >= 0 {
+		c.pivots[r], c.pivotRows[col] = -1, -1
+		if next := c.first(r, true); next >= 0 {
+			c.pivot(r, next)
+		}
+	}
+	c.Check()
+}
+
+// The eliminations are kept, rows without pivot which contain the atom get one at the next Check
+func (c *XORClause) Unassign(l types.Literal) {
+	col := c.columns[l.Atom()]
+	c.assigned[col/64] &^= 1 << (col % 64)
+	c.values[col/64] &^= 1 << (col % 64)
+	c.stale = true
+}
+
+// Queues the explanation of every changed row which implies its pivot or is a conflict
+func (c *XORClause) Check() {
+	c.revive()
+	for r := range c.rows {
+		if !c.dirty[r] {
+			continue
+		}
+		c.dirty[r] = false
+
+		unassigned, sum := c.counts(r)
+		switch {
+		case unassigned == 0 && sum != c.parity[r]:
+			c.formula.Explain(c.explanation(r, 0))
+		case unassigned == 1 && c.pivots[r] >= 0:
+			// The pivot makes up the parity of the row
+			implied := types.Literal(c.atoms[c.pivots[r]])
+			if sum == c.parity[r] {
+				implied = implied.Negate()
+			}
+			c.formula.Explain(c.explanation(r, implied))
+		}
+	}
+}
+
+/*
+AddXOR adds XOR constraints, each asking for an odd no of its literals to be true, to the Formula.
+They are kept for all later calls of Solve. The constraints of a call share their Gauss-Jordan matrix,
+so sums of them are propagated, while constraints of different calls are propagated apart.
+
+The constraints need a Formula which is a Constrainer. Proofs do not cover XOR constraints, hence they
+cannot be added while a proof is written.
+*/
+func (solver *BaseCDCLSolver) AddXOR(xors ...types.XOR) error {
+	for _, x := range xors {
+		for _, l := range x.Literals {
+			if l == 0 {
+				return handler.Throw("XOR constraint contains 0, which is not a literal", nil)
+			}
+		}
+	}
+
+	constraint, consistent := ConstructXORClause(xors)
+	switch {
+	case !consistent:
+		// Some constraints sum to 0 = 1
+		return solver.AddClause(nil)
+	case len(constraint.rows) == 0:
+		return nil
+	}
+
+	if solver.Proof != nil {
+		return handler.Throw("Proofs are not available with XOR constraints", nil)
+	}
+	f, ok := solver.F.(Constrainer)
+	if !ok {
+		return handler.Throw("The formula does not support XOR constraints", nil)
+	}
+
+	for _, a := range constraint.atoms {
+		solver.Extend(uint(a))
+	}
+	solver.Backjump(0)
+	f.Constrain(constraint)
+
+	logger.Info(fmt.Sprintf("Added %v XOR constraints over %v atoms", len(constraint.rows), len(constraint.atoms)))
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package solver_test
+
+import (
+	"math/rand"
+	"testing"
+
+	solver "github.com/alanpjohn/go-cdcl/pkg/solver"
+	types "github.com/alanpjohn/go-cdcl/pkg/types"
+)
+
+func TestXOR(t *testing.T) {
+	random := rand.New(rand.NewSource(8))
+
+	for n := 0; n < 500; n++ {
+		atoms := 3 + random.Intn(8)
+		sat := types.SATFile{AtomCount: uint(atoms)}
+		for i := random.Intn(atoms); i > 0; i-- {
+			sat.Clauses = append(sat.Clauses, randomLiterals(random, atoms, 1+random.Intn(3)))
+		}
+		for i := 1 + random.Intn(atoms); i > 0; i-- {
+			sat.XORs = append(sat.XORs, types.XOR{Literals: randomLiterals(random, atoms, 1+random.Intn(5))})
+		}
+		sat.ClauseCount = uint(len(sat.Clauses))
+
+		s, err := solver.InitializeBaseSolver(sat, solver.Options{Reduce: 20, Restart: solver.LUBY_RESTART, RestartBase: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The XOR constraints are kept across calls under different assumptions
+		for call := 0; call < 3; call++ {
+			assumptions := randomLiterals(random, atoms, call)
+			solution, err := s.Solve(assumptions...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := bruteForceCardinality(sat, assumptions); expected != (solution == types.SATISFIABLE) {
+				t.Fatalf("%+v under %v: expected satisfiable %v, found %v", sat, assumptions, expected, solution)
+			}
+			if violated, _ := solver.VerifyModel(sat, s.Model()); solution == types.SATISFIABLE && len(violated) > 0 {
+				t.Fatalf("%+v: model %v violates constraint %v", sat, s.Model(), violated[0])
+			}
+		}
+	}
+}
+
+func TestXORChain(t *testing.T) {
+	// x1 ⊕ x2, x2 ⊕ x3, ..., x40 ⊕ x1 around an odd cycle is contradictory only as a whole
+	const atoms = 41
+	var xors []types.XOR
+	for a := 1; a <= atoms; a++ {
+		xors = append(xors, types.XOR{Literals: types.Disjunction{types.Literal(a), types.Literal(a%atoms + 1)}})
+	}
+
+	s, err := solver.InitializeBaseSolver(types.SATFile{AtomCount: atoms}, solver.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.AddXOR(xors[1:]...); err != nil {
+		t.Fatal(err)
+	}
+	if solution, err := s.Solve(); err != nil || solution != types.SATISFIABLE {
+		t.Fatalf("Expected the open chain to be SATISFIABLE, found %v %v", solution, err)
+	}
+	if model := s.Model(); model[0] == model[atoms-1] {
+		t.Errorf("Expected x1 and x%v to differ in %v", atoms, model)
+	}
+
+	// Closing the chain with a separate matrix is found by search
+	if err = s.AddXOR(xors[0]); err != nil {
+		t.Fatal(err)
+	}
+	if solution, err := s.Solve(); err != nil || solution != types.UNSATISFIABLE {
+		t.Errorf("Expected the closed chain to be UNSATISFIABLE, found %v %v", solution, err)
+	}
+}
+
+func TestXORPlanted(t *testing.T) {
+	random := rand.New(rand.NewSource(9))
+
+	for n := 0; n < 10; n++ {
+		// XOR constraints and clauses over more than 64 atoms which hold in a planted model
+		const atoms = 100
+		planted := make([]bool, atoms+1)
+		for a := range planted {
+			planted[a] = random.Intn(2) == 0
+		}
+		holds := func(l types.Literal) bool { return planted[l.Atom()] == (l > 0) }
+
+		sat := types.SATFile{AtomCount: atoms}
+		for i := 0; i < 70; i++ {
+			x := types.XOR{Literals: randomLiterals(random, atoms, 2+random.Intn(6))}
+			odd := false
+			for _, l := range x.Literals {
+				odd = odd != holds(l)
+			}
+			if !odd {
+				x.Literals[0] = x.Literals[0].Negate()
+			}
+			sat.XORs = append(sat.XORs, x)
+		}
+		for len(sat.Clauses) < 120 {
+			d := randomLiterals(random, atoms, 3)
+			if holds(d[0]) || holds(d[1]) || holds(d[2]) {
+				sat.Clauses = append(sat.Clauses, d)
+			}
+		}
+
+		s, err := solver.InitializeBaseSolver(sat, solver.Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if solution, err := s.Solve(); err != nil || solution != types.SATISFIABLE {
+			t.Fatalf("Expected SATISFIABLE, found %v %v", solution, err)
+		}
+		if violated, _ := solver.VerifyModel(sat, s.Model()); len(violated) > 0 {
+			t.Fatalf("Model violates constraint %v", violated[0])
+		}
+	}
+}
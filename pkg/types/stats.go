@@ -0,0 +1,36 @@
+package types
+
+import "fmt"
+
+/*
+Stats holds the statistics of the search of a Solver over all its calls of Solve
+*/
+type Stats struct {
+	Decisions    uint    `json:"decisions"`    // No of decision literals
+	Propagations uint    `json:"propagations"` // No of literals implied by unit propagation
+	Conflicts    uint    `json:"conflicts"`    // No of conflicts
+	Restarts     uint    `json:"restarts"`     // No of restarts
+	Learnt       uint    `json:"learnt"`       // No of clauses learnt from conflicts
+	AverageLBD   float64 `json:"average_lbd"`  // Average literal block distance of the learnt clauses
+	Deleted      uint    `json:"deleted"`      // No of learnt clauses deleted
+	Memory       uint64  `json:"memory"`       // Bytes allocated on the heap when the statistics were taken
+	Time         float64 `json:"time"`         // Seconds spent in Solve
+}
+
+// Summary returns the statistics as lines of a name and a value
+func (s Stats) Summary() []string {
+	line := func(name string, value interface{}) string {
+		return fmt.Sprintf("%-13s %v", name, value)
+	}
+	return []string{
+		line("decisions", s.Decisions),
+		line("propagations", s.Propagations),
+		line("conflicts", s.Conflicts),
+		line("restarts", s.Restarts),
+		line("learnt", s.Learnt),
+		line("average lbd", fmt.Sprintf("%.2f", s.AverageLBD)),
+		line("deleted", s.Deleted),
+		line("memory", fmt.Sprintf("%.1f MB", float64(s.Memory)/(1<<20))),
+		line("time", fmt.Sprintf("%.3fs", s.Time)),
+	}
+}
@@ -26,9 +26,55 @@ Disjunction Represents a clause in CNF in propositional logic.
 type Disjunction []Literal
 
 type SATFile struct {
-	AtomCount   uint          // No of atoms
-	ClauseCount uint          // No of clauses
-	Clauses     []Disjunction // Formula read from .SAT file
+	AtomCount     uint          `json:"atom_count"`              // No of atoms
+	ClauseCount   uint          `json:"clause_count"`            // No of clauses
+	Clauses       []Disjunction `json:"clauses"`                 // Formula read from .SAT file
+	Cardinalities []Cardinality `json:"cardinalities,omitempty"` // Cardinality constraints read from `k` lines
+	XORs          []XOR         `json:"xors,omitempty"`          // XOR constraints read from `x` lines
+}
+
+// Cardinality is the constraint that at least Bound of the Literals are true
+type Cardinality struct {
+	Literals Disjunction `json:"literals"`
+	Bound    uint        `json:"bound"`
+}
+
+// XOR is the constraint that an odd no of the Literals are true
+type XOR struct {
+	Literals Disjunction `json:"literals"`
+}
+
+/*
+WCNFFile holds a weighted partial MaxSAT problem. The hard clauses must be satisfied, and the total
+weight of the soft clauses which are violated is minimized.
+*/
+type WCNFFile struct {
+	AtomCount uint          `json:"atom_count"` // No of atoms
+	Hard      []Disjunction `json:"hard"`       // Clauses which must be satisfied
+	Soft      []Disjunction `json:"soft"`       // Clauses which are violated at the cost of their weight
+	Weights   []uint64      `json:"weights"`    // Weights[i] is the weight of Soft[i]
+}
+
+// PBTerm is a literal weighted by its coefficient in a pseudo-Boolean constraint or objective
+type PBTerm struct {
+	Coefficient int64   `json:"coefficient"`
+	Literal     Literal `json:"literal"`
+}
+
+/*
+PBConstraint is the linear pseudo-Boolean constraint sum of Coefficient*Literal >= Degree,
+where a true literal counts as 1 and a false literal as 0.
+*/
+type PBConstraint struct {
+	Terms  []PBTerm `json:"terms"`
+	Degree int64    `json:"degree"`
+}
+
+// OPBFile holds a pseudo-Boolean problem, whose objective is minimized over the models of its constraints
+type OPBFile struct {
+	AtomCount   uint           `json:"atom_count"`  // No of atoms
+	Constraints []PBConstraint `json:"constraints"` // Constraints which must be satisfied
+	Objective   []PBTerm       `json:"objective"`   // Sum of the coefficients of the true literals, nil for a decision problem
 }
 
 /*
@@ -81,13 +127,17 @@ type Formula interface {
 
 // Solver interface solves a given Formula
 type Solver interface {
-	Solve() (Solution, error) // Solve
+	Solve(assumptions ...Literal) (Solution, error) // Solve under the given assumptions, which only hold for this call
+	AddClause(d Disjunction) error                  // Adds a clause to the Formula, kept for all later calls of Solve
+	Failed() []Literal                              // Assumptions responsible for the last UNSATISFIABLE result
+	Model() []Literal                               // Assignment of every atom found by the last SATISFIABLE result
+	Value(a Atom) Literal                           // Literal of the atom in the Model
 }
 
 /*
 Solution denotes the the solution found the solver.
 
-Solution can have the following values SATISFIABLE, UNSATISFIABLE, UNKNOWN
+Solution can have the following values SATISFIABLE, UNSATISFIABLE, UNKNOWN, and OPTIMUM for optimization problems
 */
 type Solution uint
 
@@ -96,6 +146,7 @@ const (
 	UNSATISFIABLE                 // No model exists that can satisfy the formula
 	PROGRESS                      // The Solver is currently working on the solution
 	UNKNOWN                       // Solver could not find a model that satifies the formula and could not estabilish unsatisfiability due to some error
+	OPTIMUM                       // A model of minimum cost was found for an optimization problem
 )
 
 func (s Solution) String() string {
@@ -104,6 +155,8 @@ func (s Solution) String() string {
 		return "SATISFIABLE"
 	case 1:
 		return "UNSATISFIABLE"
+	case 4:
+		return "OPTIMUM FOUND"
 	}
 	return "UNKNOWN"
 }